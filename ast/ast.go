@@ -2,6 +2,7 @@ package ast
 
 import (
 	"bytes"
+	"strings"
 )
 
 type Node interface {
@@ -43,3 +44,16 @@ func (p *Program) TokenLiteral() string {
 		return ""
 	}
 }
+
+// Maximum indentation level printed by ToString. Deeper nodes are printed as "...", so
+// huge trees do not produce endless outputs.
+var MaxPrintLevel = 200
+
+// Prints a child node, replacing it with an ellipsis when it is nested too deep
+func printNode(node Node, lvl int) string {
+	if lvl > MaxPrintLevel {
+		return strings.Repeat("  ", lvl) + "...\n"
+	}
+
+	return node.ToString(lvl)
+}
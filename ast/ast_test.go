@@ -36,3 +36,24 @@ func TestString(t *testing.T) {
 		t.Errorf("program.ToString() wrong.\nExpected:\n%s\nGot:\n%s", expected, actual)
 	}
 }
+
+func TestStringMaxLevel(t *testing.T) {
+	defer func(lvl int) { MaxPrintLevel = lvl }(MaxPrintLevel)
+	MaxPrintLevel = 4
+
+	var exp Expression = &IntegerLiteral{Value: 1, Token: tokens.Token{Type: tokens.NUMBER, Literal: "1"}}
+	for i := 0; i < 10000; i++ {
+		exp = &PrefixExpression{Operator: "-", Right: exp, Token: tokens.Token{Type: tokens.MINUS, Literal: "-"}}
+	}
+
+	actual := strings.TrimSpace(exp.ToString(0))
+	lines := strings.Split(actual, "\n")
+
+	if !strings.HasSuffix(actual, "...") {
+		t.Errorf("Expected the deepest node to be printed as '...'. Got:\n%s", actual)
+	}
+
+	if len(lines) > 10 {
+		t.Errorf("Expected the output to be cut at level %d. Got %d lines", MaxPrintLevel, len(lines))
+	}
+}
@@ -94,7 +94,7 @@ func (p *PrefixExpression) ToString(lvl int) string {
 	out.WriteString(indent + "prefix expression:\n")
 	out.WriteString(indent + " operator: " + p.Operator + "\n")
 	out.WriteString(indent + " right:\n")
-	out.WriteString(p.Right.ToString(lvl + 2)) // Increase indentation for the right expression
+	out.WriteString(printNode(p.Right, lvl+2)) // Increase indentation for the right expression
 
 	return out.String()
 }
@@ -116,10 +116,10 @@ func (i *InfixExpression) ToString(lvl int) string {
 	indent := strings.Repeat("  ", lvl)
 	out.WriteString(indent + "infix expression:\n")
 	out.WriteString(indent + " left:\n")
-	out.WriteString(i.Left.ToString(lvl+2)) // Increase indentation for the left expression
+	out.WriteString(printNode(i.Left, lvl+2)) // Increase indentation for the left expression
 	out.WriteString(indent + " operator: " + i.Operator + "\n")
 	out.WriteString(indent + " right:\n")
-	out.WriteString(i.Right.ToString(lvl + 2)) // Increase indentation for the right expression
+	out.WriteString(printNode(i.Right, lvl+2)) // Increase indentation for the right expression
 
 	return out.String()
 }
@@ -147,6 +147,19 @@ func (b *Boolean) ToString(lvl int) string {
 	return fmt.Sprintf("%sBool: %s\n", indent, b.TokenLiteral())
 }
 
+type NullLiteral struct {
+	Token tokens.Token
+}
+
+func (n *NullLiteral) expressionNode() {}
+func (n *NullLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+func (n *NullLiteral) ToString(lvl int) string {
+	indent := strings.Repeat("  ", lvl)
+	return fmt.Sprintf("%sNull\n", indent)
+}
+
 type IfExpression struct {
 	Condition   Expression
 	Consequence *BlockStatement
@@ -170,13 +183,13 @@ func (i *IfExpression) ToString(lvl int) string {
 	indent := strings.Repeat("  ", lvl)
 	buffer.WriteString(indent + "if expression:\n")
 	buffer.WriteString(indent + "  condition:\n")
-	buffer.WriteString(i.Condition.ToString(lvl + 2)) // Increase indentation for the condition
+	buffer.WriteString(printNode(i.Condition, lvl+2)) // Increase indentation for the condition
 	buffer.WriteString(indent + "  consequence:\n")
-	buffer.WriteString(i.Consequence.ToString(lvl + 2)) // Increase indentation for the consequence
+	buffer.WriteString(printNode(i.Consequence, lvl+2)) // Increase indentation for the consequence
 
 	if i.Alternative != nil {
 		buffer.WriteString(indent + "  alternative:\n")
-		buffer.WriteString(i.Alternative.ToString(lvl + 2)) // Increase indentation for the alternative
+		buffer.WriteString(printNode(i.Alternative, lvl+2)) // Increase indentation for the alternative
 	}
 
 	return buffer.String()
@@ -205,10 +218,10 @@ func (f *AnonymousFunction) ToString(lvl int) string {
 	buffer.WriteString(indent + "anonymous function:\n")
 	buffer.WriteString(indent + "  parameters:\n")
 	for _, v := range f.Parameters {
-		buffer.WriteString(indent + "    " + v.ToString(lvl) + "\n")
+		buffer.WriteString(indent + "    " + printNode(v, lvl) + "\n")
 	}
 	buffer.WriteString(indent + "  body:\n")
-	buffer.WriteString(f.Body.ToString(lvl + 2)) // Increase indentation for the body
+	buffer.WriteString(printNode(f.Body, lvl+2)) // Increase indentation for the body
 
 	return buffer.String()
 }
@@ -235,13 +248,78 @@ func (f *FunctionCall) ToString(lvl int) string {
 
 	indent := strings.Repeat("  ", lvl)
 	buffer.WriteString(indent + "function call:\n")
-	buffer.WriteString(f.Identifier.ToString(lvl))
+	buffer.WriteString(printNode(f.Identifier, lvl))
 
 	// Print the arguments
 	buffer.WriteString(indent + "  arguments:\n")
 	for _, arg := range f.Arguments {
-		buffer.WriteString(arg.ToString(lvl+2) + "\n") // Increase indentation for arguments
+		buffer.WriteString(printNode(arg, lvl+2) + "\n") // Increase indentation for arguments
+	}
+
+	return buffer.String()
+}
+
+// Iterates over the items of a collection: repetir item en items { ... }
+type ForInLoop struct {
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+	Label    *Identifier // nil for loops without label
+	Token    tokens.Token
+}
+
+func NewForInLoop(t tokens.Token) *ForInLoop {
+	return &ForInLoop{
+		Token: t,
 	}
+}
+
+func (f *ForInLoop) expressionNode() {}
+func (f *ForInLoop) TokenLiteral() string {
+	return f.Token.Literal
+}
+func (f *ForInLoop) ToString(lvl int) string {
+	var buffer bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+
+	buffer.WriteString(indent + "for in loop" + labelString(f.Label) + ":\n")
+	buffer.WriteString(indent + " variable: " + f.Variable.Value + "\n")
+	buffer.WriteString(indent + " iterable:\n")
+	buffer.WriteString(printNode(f.Iterable, lvl+2))
+	buffer.WriteString(indent + " body:\n")
+	buffer.WriteString(printNode(f.Body, lvl+2))
+
+	return buffer.String()
+}
+
+type WhileLoop struct {
+	Condition Expression
+	Body      *BlockStatement
+	Label     *Identifier // nil for loops without label
+	Token     tokens.Token
+}
+
+func NewWhileLoop(t tokens.Token) *WhileLoop {
+	return &WhileLoop{
+		Token: t,
+	}
+}
+
+func (w *WhileLoop) expressionNode() {}
+func (w *WhileLoop) TokenLiteral() string {
+	return w.Token.Literal
+}
+func (w *WhileLoop) ToString(lvl int) string {
+	var buffer bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+
+	buffer.WriteString(indent + "while loop" + labelString(w.Label) + ":\n")
+	buffer.WriteString(indent + " condition:\n")
+	buffer.WriteString(printNode(w.Condition, lvl+2))
+	buffer.WriteString(indent + " body:\n")
+	buffer.WriteString(printNode(w.Body, lvl+2))
 
 	return buffer.String()
 }
@@ -249,6 +327,7 @@ func (f *FunctionCall) ToString(lvl int) string {
 type ForLoop struct {
 	Iterations IntegerLiteral
 	Body       *BlockStatement
+	Label      *Identifier // nil for loops without label
 	Token      tokens.Token
 }
 
@@ -267,10 +346,166 @@ func (f *ForLoop) ToString(lvl int) string {
 
 	indent := strings.Repeat("  ", lvl)
 
-	buffer.WriteString(indent + "for loop:\n")
+	buffer.WriteString(indent + "for loop" + labelString(f.Label) + ":\n")
 	buffer.WriteString(indent + " iterations: " + f.Iterations.ToString(0) + "\n")
 	buffer.WriteString(indent + " body:\n")
-	buffer.WriteString(f.Body.ToString(lvl + 2))
+	buffer.WriteString(printNode(f.Body, lvl+2))
 
 	return buffer.String()
 }
+
+type ArrayLiteral struct {
+	Elements []Expression
+	Token    tokens.Token // the "[" token
+}
+
+func NewArrayLiteral(t tokens.Token) *ArrayLiteral {
+	return &ArrayLiteral{
+		Token: t,
+	}
+}
+
+func (a *ArrayLiteral) expressionNode() {}
+func (a *ArrayLiteral) TokenLiteral() string {
+	return a.Token.Literal
+}
+func (a *ArrayLiteral) ToString(lvl int) string {
+	var buffer bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+	buffer.WriteString(indent + "array literal:\n")
+	buffer.WriteString(indent + "  elements:\n")
+	for _, el := range a.Elements {
+		buffer.WriteString(printNode(el, lvl+2))
+	}
+
+	return buffer.String()
+}
+
+// Key-value pair of a hash literal
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+type HashLiteral struct {
+	Pairs []HashPair   // pairs are stored in the same order as declared
+	Token tokens.Token // the "{" token
+}
+
+func NewHashLiteral(t tokens.Token) *HashLiteral {
+	return &HashLiteral{
+		Token: t,
+	}
+}
+
+func (h *HashLiteral) expressionNode() {}
+func (h *HashLiteral) TokenLiteral() string {
+	return h.Token.Literal
+}
+func (h *HashLiteral) ToString(lvl int) string {
+	var buffer bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+	buffer.WriteString(indent + "hash literal:\n")
+	for _, pair := range h.Pairs {
+		buffer.WriteString(indent + "  key:\n")
+		buffer.WriteString(printNode(pair.Key, lvl+2))
+		buffer.WriteString(indent + "  value:\n")
+		buffer.WriteString(printNode(pair.Value, lvl+2))
+	}
+
+	return buffer.String()
+}
+
+// Index access like: array[0] or hash["key"]
+type IndexExpression struct {
+	Left  Expression
+	Index Expression
+	Token tokens.Token // the "[" token
+}
+
+func NewIndexExpression(t tokens.Token, left Expression) *IndexExpression {
+	return &IndexExpression{
+		Token: t,
+		Left:  left,
+	}
+}
+
+func (i *IndexExpression) expressionNode() {}
+func (i *IndexExpression) TokenLiteral() string {
+	return i.Token.Literal
+}
+func (i *IndexExpression) ToString(lvl int) string {
+	var out bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+	out.WriteString(indent + "index expression:\n")
+	out.WriteString(indent + " left:\n")
+	out.WriteString(printNode(i.Left, lvl+2))
+	out.WriteString(indent + " index:\n")
+	out.WriteString(printNode(i.Index, lvl+2))
+
+	return out.String()
+}
+
+// Member access like: object.member
+type MemberExpression struct {
+	Left   Expression
+	Member *Identifier
+	Token  tokens.Token // the "." token
+}
+
+func NewMemberExpression(t tokens.Token, left Expression) *MemberExpression {
+	return &MemberExpression{
+		Token: t,
+		Left:  left,
+	}
+}
+
+func (m *MemberExpression) expressionNode() {}
+func (m *MemberExpression) TokenLiteral() string {
+	return m.Token.Literal
+}
+func (m *MemberExpression) ToString(lvl int) string {
+	var out bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+	out.WriteString(indent + "member expression:\n")
+	out.WriteString(indent + " left:\n")
+	out.WriteString(printNode(m.Left, lvl+2))
+	out.WriteString(indent + " member: " + m.Member.Value + "\n")
+
+	return out.String()
+}
+
+// Assignment to an already declared variable or to an index: a = b[0] = 1
+type AssignExpression struct {
+	Target Expression // an *Identifier or an *IndexExpression
+	Value  Expression
+	Token  tokens.Token // the "=" token
+}
+
+func NewAssignExpression(t tokens.Token, target Expression) *AssignExpression {
+	return &AssignExpression{
+		Token:  t,
+		Target: target,
+	}
+}
+
+func (a *AssignExpression) expressionNode() {}
+func (a *AssignExpression) TokenLiteral() string {
+	return a.Token.Literal
+}
+func (a *AssignExpression) ToString(lvl int) string {
+	var out bytes.Buffer
+
+	indent := strings.Repeat("  ", lvl)
+	out.WriteString(indent + "assign expression:\n")
+	out.WriteString(indent + " target:\n")
+	out.WriteString(printNode(a.Target, lvl+2))
+	out.WriteString(indent + " value:\n")
+	out.WriteString(printNode(a.Value, lvl+2))
+
+	return out.String()
+}
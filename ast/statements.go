@@ -26,11 +26,11 @@ func (v *VarStatement) ToString(lvl int) string {
 
 	indent := strings.Repeat("  ", lvl)
 	out.WriteString(indent + "var statement:\n")
-	out.WriteString(v.Identifier.ToString(lvl+1))
+	out.WriteString(printNode(v.Identifier, lvl+1))
 	out.WriteString(indent + "  value: \n")
 
 	if v.Value != nil {
-		out.WriteString(v.Value.ToString(lvl + 2))
+		out.WriteString(printNode(v.Value, lvl+2))
 	} else {
 		out.WriteString("nil")
 	}
@@ -56,7 +56,7 @@ func (r *ReturnStatement) ToString(lvl int) string {
 	out.WriteString(indent + "  value: \n")
 
 	if r.ReturnValue != nil {
-		out.WriteString(r.ReturnValue.ToString(lvl + 2))
+		out.WriteString(printNode(r.ReturnValue, lvl+2))
 	} else {
 		out.WriteString("nil")
 	}
@@ -64,6 +64,42 @@ func (r *ReturnStatement) ToString(lvl int) string {
 	return out.String()
 }
 
+// Stops the innermost loop, or the loop with the given label: romper externo
+type BreakStatement struct {
+	Label *Identifier // nil when the statement has no label
+	Token tokens.Token
+}
+
+func (b *BreakStatement) statementNode() {}
+func (b *BreakStatement) TokenLiteral() string {
+	return b.Token.Literal
+}
+func (b *BreakStatement) ToString(lvl int) string {
+	return strings.Repeat("  ", lvl) + "break statement" + labelString(b.Label) + "\n"
+}
+
+// Skips to the next iteration of the innermost loop, or the loop with the given label
+type ContinueStatement struct {
+	Label *Identifier // nil when the statement has no label
+	Token tokens.Token
+}
+
+func (c *ContinueStatement) statementNode() {}
+func (c *ContinueStatement) TokenLiteral() string {
+	return c.Token.Literal
+}
+func (c *ContinueStatement) ToString(lvl int) string {
+	return strings.Repeat("  ", lvl) + "continue statement" + labelString(c.Label) + "\n"
+}
+
+func labelString(label *Identifier) string {
+	if label == nil {
+		return ""
+	}
+
+	return " (label: " + label.Value + ")"
+}
+
 /*
 An expression statement is a expression which is not assosiated to a variable
 declaration like: -(5+5)
@@ -82,7 +118,7 @@ func (e *ExpressionStatement) ToString(lvl int) string {
 
 	indent := strings.Repeat("  ", lvl)
 	out.WriteString(indent + "expression statement:\n")
-	out.WriteString(indent + " expression: \n" + e.Expression.ToString(lvl+2))
+	out.WriteString(indent + " expression: \n" + printNode(e.Expression, lvl+2))
 
 	return out.String()
 }
@@ -102,7 +138,7 @@ func (b *BlockStatement) ToString(lvl int) string {
 	indent := strings.Repeat("  ", lvl)
 	buffer.WriteString(indent + "block statement:\n")
 	for _, stmt := range b.Statements {
-		buffer.WriteString(stmt.ToString(lvl + 1) + "\n")
+		buffer.WriteString(printNode(stmt, lvl+1) + "\n")
 	}
 
 	return buffer.String()
@@ -131,13 +167,13 @@ func (f *FunctionStatement) ToString(lvl int) string {
 
 	indent := strings.Repeat("  ", lvl)
 	buffer.WriteString(indent + "function statement:\n")
-	buffer.WriteString("  " + f.Identifier.ToString(lvl))
+	buffer.WriteString("  " + printNode(f.Identifier, lvl))
 	buffer.WriteString(indent + "  parameters:\n")
 	for _, v := range f.Parameters {
-		buffer.WriteString(v.ToString(lvl+3))
+		buffer.WriteString(printNode(v, lvl+3))
 	}
 	buffer.WriteString(indent + "  body:\n")
-	buffer.WriteString(f.Body.ToString(lvl + 2))
+	buffer.WriteString(printNode(f.Body, lvl+2))
 
 	return buffer.String()
 }
@@ -0,0 +1,1357 @@
+package evaluator
+
+import (
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sl2.0/objects"
+)
+
+// Signature of the functions implemented natively by the interpreter. They recieve the
+// evaluator and the caller environment so they can call back user defined functions.
+type BuiltinFunction func(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object
+
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (b *Builtin) Type() objects.ObjectType {
+	return objects.BUILTIN_OBJ
+}
+func (b *Builtin) Inspect() string {
+	return "builtin function: " + b.Name
+}
+
+// Builtin functions available on every program. Identifiers declared by the user take
+// precedence over this ones.
+var builtins map[string]*Builtin
+
+// builtins are registered on init because some of them call back the evaluator, which
+// would create an initialization cycle.
+func init() {
+	builtins = map[string]*Builtin{
+		"partial": {Name: "partial", Fn: builtinPartial},
+		"compose": {Name: "compose", Fn: builtinCompose},
+		"curry":   {Name: "curry", Fn: builtinCurry},
+
+		"char_code":      {Name: "char_code", Fn: builtinCharCode},
+		"from_char_code": {Name: "from_char_code", Fn: builtinFromCharCode},
+
+		"unique": {Name: "unique", Fn: builtinUnique},
+
+		"dedup_consecutive": {Name: "dedup_consecutive", Fn: builtinDedupConsecutive},
+		"run_length":        {Name: "run_length", Fn: builtinRunLength},
+
+		"slice":  {Name: "slice", Fn: builtinSlice},
+		"splice": {Name: "splice", Fn: builtinSplice},
+		"chunk":  {Name: "chunk", Fn: builtinChunk},
+		"swap":   {Name: "swap", Fn: builtinSwap},
+
+		// equality is always structural, deep_equal only makes it explicit
+		"equals":     {Name: "equals", Fn: builtinEquals("equals")},
+		"deep_equal": {Name: "deep_equal", Fn: builtinEquals("deep_equal")},
+
+		"find":       {Name: "find", Fn: builtinFind},
+		"find_index": {Name: "find_index", Fn: builtinFindIndex},
+		"index_all":  {Name: "index_all", Fn: builtinIndexAll},
+		"take_while": {Name: "take_while", Fn: builtinTakeWhile},
+		"drop_while": {Name: "drop_while", Fn: builtinDropWhile},
+		"flat_map":   {Name: "flat_map", Fn: builtinFlatMap},
+		"sort_by":    {Name: "sort_by", Fn: builtinSortBy},
+		"zip_with":   {Name: "zip_with", Fn: builtinZipWith},
+
+		"to_bool": {Name: "to_bool", Fn: builtinToBool},
+		"range":   {Name: "range", Fn: builtinRange},
+
+		"range_sum": {Name: "range_sum", Fn: builtinRangeSum},
+
+		"array_fill": {Name: "array_fill", Fn: builtinArrayFill},
+		"make_array": {Name: "make_array", Fn: builtinMakeArray},
+
+		"first": {Name: "first", Fn: builtinFirst},
+		"last":  {Name: "last", Fn: builtinLast},
+		"rest":  {Name: "rest", Fn: builtinRest},
+		"init":  {Name: "init", Fn: builtinInit},
+
+		"keys":    {Name: "keys", Fn: builtinKeys},
+		"entries": {Name: "entries", Fn: builtinEntries},
+		"merge":   {Name: "merge", Fn: builtinMerge},
+
+		"frequency": {Name: "frequency", Fn: builtinFrequency},
+
+		"now":   {Name: "now", Fn: builtinNow},
+		"retry": {Name: "retry", Fn: builtinRetry},
+		"print": {Name: "print", Fn: builtinPrint},
+
+		"repeat_string": {Name: "repeat_string", Fn: builtinRepeatString},
+
+		"to_json":   {Name: "to_json", Fn: builtinToJson},
+		"from_json": {Name: "from_json", Fn: builtinFromJson},
+	}
+}
+
+// Methods of each type, accessed with "value.method". The receiver is passed as the first
+// argument.
+var methods map[objects.ObjectType]map[string]BuiltinFunction
+
+func init() {
+	methods = map[objects.ObjectType]map[string]BuiltinFunction{
+		objects.INTEGER_OBJ: {
+			"times": methodTimes,
+		},
+	}
+}
+
+// Returns a builtin that calls the method with the receiver prepended to the arguments
+func bindMethod(name string, method BuiltinFunction, receiver objects.Object) *Builtin {
+	return &Builtin{
+		Name: name,
+		Fn: func(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+			callArgs := make([]objects.Object, 0, len(args)+1)
+			callArgs = append(callArgs, receiver)
+			callArgs = append(callArgs, args...)
+
+			return method(e, env, callArgs...)
+		},
+	}
+}
+
+// n.times(fn) calls "fn" n times with the index of the iteration (starting from 0)
+func methodTimes(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'times'. Expected 1, got %d", len(args)-1)
+	}
+
+	n := args[0].(*objects.Integer)
+
+	fn := args[1]
+	if !isCallable(fn) {
+		return objects.NewTypeError(
+			"Argument of 'times' must be a function. \n\tGot: %s", fn.Type())
+	}
+
+	for i := int64(0); i < n.Value; i++ {
+		res := e.applyFunction(fn, []objects.Object{&objects.Integer{Value: i}}, env)
+		if isError(res) {
+			return res
+		}
+	}
+
+	return null_obj
+}
+
+// partial(fn, args...) returns a new function that calls "fn" with the given arguments
+// prepended to the ones recieved on the call.
+func builtinPartial(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) < 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'partial'. Expected at least 1, got %d", len(args))
+	}
+
+	fn := args[0]
+	if !isCallable(fn) {
+		return objects.NewTypeError(
+			"First argument of 'partial' must be a function. \n\tGot: %s", fn.Type())
+	}
+
+	bound := args[1:]
+
+	return &Builtin{
+		Name: "partial",
+		Fn: func(e *Evaluator, env *objects.Storage, rest ...objects.Object) objects.Object {
+			callArgs := make([]objects.Object, 0, len(bound)+len(rest))
+			callArgs = append(callArgs, bound...)
+			callArgs = append(callArgs, rest...)
+
+			return e.applyFunction(fn, callArgs, env)
+		},
+	}
+}
+
+// compose(f, g, h) returns a function equivalent to "x => f(g(h(x)))". Every function is
+// applied from right to left and the first error stops the pipeline.
+func builtinCompose(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	for i, fn := range args {
+		if !isCallable(fn) {
+			return objects.NewTypeError(
+				"Argument %d of 'compose' must be a function. \n\tGot: %s", i+1, fn.Type())
+		}
+
+		if f, ok := fn.(*objects.FunctionObject); ok && len(f.Parameters) != 1 {
+			return objects.NewArgumentError(
+				"Argument %d of 'compose' must be a single argument function. \n\tGot: %d parameters",
+				i+1, len(f.Parameters))
+		}
+	}
+
+	fns := args
+
+	return &Builtin{
+		Name: "compose",
+		Fn: func(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+			if len(args) != 1 {
+				return objects.NewArgumentError(
+					"Wrong number of arguments for composed function. Expected 1, got %d", len(args))
+			}
+
+			value := args[0]
+			for i := len(fns) - 1; i >= 0; i-- {
+				value = e.applyFunction(fns[i], []objects.Object{value}, env)
+				if isError(value) {
+					return value
+				}
+			}
+
+			return value
+		},
+	}
+}
+
+// curry(fn) turns a function of n parameters into a chain of single argument functions,
+// so "curry(add)(1)(2)" is the same as "add(1, 2)". The function is called once every
+// parameter recieved its argument.
+func builtinCurry(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'curry'. Expected 1, got %d", len(args))
+	}
+
+	// the arity of builtins is unknown, so only user functions can be curried
+	fn, ok := args[0].(*objects.FunctionObject)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'curry' must be a user defined function. \n\tGot: %s", args[0].Type())
+	}
+
+	if len(fn.Parameters) < 2 {
+		return fn
+	}
+
+	return curryStep(fn, nil)
+}
+
+// Returns the function that recieves the next argument of the curried function
+func curryStep(fn *objects.FunctionObject, bound []objects.Object) *Builtin {
+	return &Builtin{
+		Name: "curry",
+		Fn: func(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+			if len(args) != 1 {
+				return objects.NewArgumentError(
+					"Wrong number of arguments for curried function. Expected 1, got %d", len(args))
+			}
+
+			// every step gets its own copy, so a partially applied function can be reused
+			callArgs := make([]objects.Object, 0, len(bound)+1)
+			callArgs = append(callArgs, bound...)
+			callArgs = append(callArgs, args[0])
+
+			if len(callArgs) < len(fn.Parameters) {
+				return curryStep(fn, callArgs)
+			}
+
+			return e.applyFunction(fn, callArgs, env)
+		},
+	}
+}
+
+// char_code(s) returns the unicode code point of a single character string
+func builtinCharCode(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'char_code'. Expected 1, got %d", len(args))
+	}
+
+	str, ok := args[0].(*objects.String)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'char_code' must be a string. \n\tGot: %s", args[0].Type())
+	}
+
+	if utf8.RuneCountInString(str.Value) != 1 {
+		return objects.NewArgumentError(
+			"Argument of 'char_code' must be a single character. \n\tGot: %q", str.Value)
+	}
+
+	r, _ := utf8.DecodeRuneInString(str.Value)
+
+	return &objects.Integer{Value: int64(r)}
+}
+
+// from_char_code(n) returns the single character string of the given unicode code point
+func builtinFromCharCode(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'from_char_code'. Expected 1, got %d", len(args))
+	}
+
+	code, ok := args[0].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'from_char_code' must be an integer. \n\tGot: %s", args[0].Type())
+	}
+
+	if code.Value < 0 || code.Value > utf8.MaxRune || !utf8.ValidRune(rune(code.Value)) {
+		return objects.NewArgumentError("Invalid code point: %d", code.Value)
+	}
+
+	return &objects.String{Value: string(rune(code.Value))}
+}
+
+// unique(arr) returns a new array without duplicated elements, keeping the first
+// occurrence of each one. Hashable elements are compared by its hash key, and the rest
+// with structural equality.
+func builtinUnique(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'unique'. Expected 1, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'unique' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	seen := make(map[objects.HashKey]bool)
+	var unhashable []objects.Object
+
+	elements := []objects.Object{}
+	for _, el := range array.Elements {
+		if hashable, ok := el.(objects.Hashable); ok {
+			key := hashable.HashKey()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		} else {
+			if containsObject(unhashable, el) {
+				continue
+			}
+			unhashable = append(unhashable, el)
+		}
+
+		elements = append(elements, el)
+	}
+
+	return &objects.Array{Elements: elements}
+}
+
+// reports if the list contains an object structurally equal to the given one
+func containsObject(list []objects.Object, obj objects.Object) bool {
+	for _, el := range list {
+		if objects.Equals(el, obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dedup_consecutive(arr) returns a new array where every run of adjacent equal elements is
+// replaced by a single element. Unlike unique, repeated elements that are not adjacent are
+// kept.
+func builtinDedupConsecutive(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'dedup_consecutive'. Expected 1, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'dedup_consecutive' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	values, _ := adjacentRuns(array.Elements)
+
+	return &objects.Array{Elements: values}
+}
+
+// run_length(arr) returns a [value, count] pair for every run of adjacent equal elements
+func builtinRunLength(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'run_length'. Expected 1, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'run_length' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	values, counts := adjacentRuns(array.Elements)
+
+	pairs := make([]objects.Object, len(values))
+	for i, value := range values {
+		pairs[i] = &objects.Array{
+			Elements: []objects.Object{value, &objects.Integer{Value: counts[i]}},
+		}
+	}
+
+	return &objects.Array{Elements: pairs}
+}
+
+// Groups the adjacent elements that are structurally equal, returning the first element
+// and the length of every run.
+func adjacentRuns(elements []objects.Object) ([]objects.Object, []int64) {
+	values := []objects.Object{}
+	var counts []int64
+
+	for _, el := range elements {
+		last := len(values) - 1
+		if last >= 0 && objects.Equals(values[last], el) {
+			counts[last]++
+			continue
+		}
+
+		values = append(values, el)
+		counts = append(counts, 1)
+	}
+
+	return values, counts
+}
+
+// slice(arr, start, end) returns a new array with the elements from "start" up to (but
+// not including) "end".
+func builtinSlice(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 3 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'slice'. Expected 3, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'slice' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	start, ok := args[1].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Second argument of 'slice' must be an integer. \n\tGot: %s", args[1].Type())
+	}
+
+	end, ok := args[2].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Third argument of 'slice' must be an integer. \n\tGot: %s", args[2].Type())
+	}
+
+	length := int64(len(array.Elements))
+	if start.Value < 0 || start.Value > length {
+		return objects.NewIndexError("Index out of range: %d", start.Value)
+	}
+
+	if end.Value < start.Value || end.Value > length {
+		return objects.NewIndexError("Index out of range: %d", end.Value)
+	}
+
+	elements := make([]objects.Object, end.Value-start.Value)
+	copy(elements, array.Elements[start.Value:end.Value])
+
+	return &objects.Array{Elements: elements}
+}
+
+// swap(arr, i, j) exchanges the elements on the given indexes. The array is modified in
+// place.
+func builtinSwap(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 3 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'swap'. Expected 3, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'swap' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	var indexes [2]int64
+	for i, arg := range args[1:] {
+		idx, ok := arg.(*objects.Integer)
+		if !ok {
+			return objects.NewTypeError(
+				"Indexes of 'swap' must be integers. \n\tGot: %s", describeObject(arg))
+		}
+
+		if idx.Value < 0 || idx.Value >= int64(len(array.Elements)) {
+			return objects.NewIndexError("Index out of range: %d", idx.Value)
+		}
+
+		indexes[i] = idx.Value
+	}
+
+	i, j := indexes[0], indexes[1]
+	array.Elements[i], array.Elements[j] = array.Elements[j], array.Elements[i]
+
+	return null_obj
+}
+
+// chunk(arr, size) splits the array into groups of "size" elements. The last group has the
+// remaining elements, so it can be shorter.
+func builtinChunk(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'chunk'. Expected 2, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'chunk' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	size, ok := args[1].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Second argument of 'chunk' must be an integer. \n\tGot: %s", args[1].Type())
+	}
+
+	if size.Value <= 0 {
+		return objects.NewArgumentError("Size of 'chunk' must be greater than 0. Got %d", size.Value)
+	}
+
+	length := int64(len(array.Elements))
+	step := min(size.Value, length) // avoids overflows with huge sizes
+
+	chunks := []objects.Object{}
+	for start := int64(0); start < length; start += step {
+		end := min(start+step, length)
+
+		elements := make([]objects.Object, end-start)
+		copy(elements, array.Elements[start:end])
+		chunks = append(chunks, &objects.Array{Elements: elements})
+	}
+
+	return &objects.Array{Elements: chunks}
+}
+
+// splice(arr, start, delete_count, items...) removes "delete_count" elements starting
+// from "start" and inserts the given items on their place. The array is modified in place
+// and the removed elements are returned.
+func builtinSplice(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) < 3 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'splice'. Expected at least 3, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'splice' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	start, ok := args[1].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Second argument of 'splice' must be an integer. \n\tGot: %s", args[1].Type())
+	}
+
+	count, ok := args[2].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Third argument of 'splice' must be an integer. \n\tGot: %s", args[2].Type())
+	}
+
+	length := int64(len(array.Elements))
+	if start.Value < 0 || start.Value > length {
+		return objects.NewIndexError("Index out of range: %d", start.Value)
+	}
+
+	if count.Value < 0 || start.Value+count.Value > length {
+		return objects.NewArgumentError("Invalid number of elements to remove: %d", count.Value)
+	}
+
+	end := start.Value + count.Value
+
+	if err := e.checkArraySize(length - count.Value + int64(len(args)-3)); err != nil {
+		return err
+	}
+
+	removed := make([]objects.Object, count.Value)
+	copy(removed, array.Elements[start.Value:end])
+
+	items := args[3:]
+	elements := make([]objects.Object, 0, length-count.Value+int64(len(items)))
+	elements = append(elements, array.Elements[:start.Value]...)
+	elements = append(elements, items...)
+	elements = append(elements, array.Elements[end:]...)
+
+	array.Elements = elements
+
+	return &objects.Array{Elements: removed}
+}
+
+// equals(a, b) and deep_equal(a, b) compare any two values with structural equality. The
+// name is the one of the builtin, used on the error messages.
+func builtinEquals(name string) BuiltinFunction {
+	return func(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+		if len(args) != 2 {
+			return objects.NewArgumentError(
+				"Wrong number of arguments for '%s'. Expected 2, got %d", name, len(args))
+		}
+
+		return selectBoolObject(objects.Equals(args[0], args[1]))
+	}
+}
+
+// find(arr, fn) returns the first element for which "fn" returns true, or null
+func builtinFind(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("find", args)
+	if err != nil {
+		return err
+	}
+
+	idx, err := e.findIndex("find", array, fn, env)
+	if err != nil {
+		return err
+	}
+
+	if idx < 0 {
+		return null_obj
+	}
+
+	return array.Elements[idx]
+}
+
+// find_index(arr, fn) returns the index of the first element for which "fn" returns
+// true, or -1
+func builtinFindIndex(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("find_index", args)
+	if err != nil {
+		return err
+	}
+
+	idx, err := e.findIndex("find_index", array, fn, env)
+	if err != nil {
+		return err
+	}
+
+	return &objects.Integer{Value: int64(idx)}
+}
+
+// index_all(collection, value) returns every index where the value occurs. Array elements
+// are compared with structural equality, while on strings every position (even
+// overlapping ones) where the substring starts is returned.
+func builtinIndexAll(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'index_all'. Expected 2, got %d", len(args))
+	}
+
+	indexes := []objects.Object{}
+
+	switch collection := args[0].(type) {
+	case *objects.Array:
+		for i, el := range collection.Elements {
+			if objects.Equals(el, args[1]) {
+				indexes = append(indexes, &objects.Integer{Value: int64(i)})
+			}
+		}
+
+	case *objects.String:
+		sub, ok := args[1].(*objects.String)
+		if !ok {
+			return objects.NewTypeError(
+				"Second argument of 'index_all' must be a string when searching a string. \n\tGot: %s",
+				describeObject(args[1]))
+		}
+
+		if sub.Value == "" {
+			return objects.NewArgumentError("Cannot search an empty string with 'index_all'")
+		}
+
+		// indexes are counted in characters, like on string indexing
+		str, target := []rune(collection.Value), []rune(sub.Value)
+		for i := 0; i+len(target) <= len(str); i++ {
+			if slices.Equal(str[i:i+len(target)], target) {
+				indexes = append(indexes, &objects.Integer{Value: int64(i)})
+			}
+		}
+
+	default:
+		return objects.NewTypeError(
+			"First argument of 'index_all' must be an array or a string. \n\tGot: %s",
+			describeObject(args[0]))
+	}
+
+	return &objects.Array{Elements: indexes}
+}
+
+// Returns the index of the first element that satisfies the predicate or -1
+func (e *Evaluator) findIndex(
+	name string, array *objects.Array, fn objects.Object, env *objects.Storage,
+) (int, objects.Object) {
+	for i, el := range array.Elements {
+		ok, err := e.callPredicate(name, fn, el, env)
+		if err != nil {
+			return -1, err
+		}
+
+		if ok {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// take_while(arr, fn) returns a new array with the leading elements for which "fn"
+// returns true
+func builtinTakeWhile(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("take_while", args)
+	if err != nil {
+		return err
+	}
+
+	end, err := e.leadingRun("take_while", array, fn, env)
+	if err != nil {
+		return err
+	}
+
+	elements := make([]objects.Object, end)
+	copy(elements, array.Elements[:end])
+
+	return &objects.Array{Elements: elements}
+}
+
+// drop_while(arr, fn) returns a new array without the leading elements for which "fn"
+// returns true
+func builtinDropWhile(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("drop_while", args)
+	if err != nil {
+		return err
+	}
+
+	start, err := e.leadingRun("drop_while", array, fn, env)
+	if err != nil {
+		return err
+	}
+
+	elements := make([]objects.Object, len(array.Elements)-start)
+	copy(elements, array.Elements[start:])
+
+	return &objects.Array{Elements: elements}
+}
+
+// Returns the length of the leading run of elements that satisfy the predicate
+func (e *Evaluator) leadingRun(
+	name string, array *objects.Array, fn objects.Object, env *objects.Storage,
+) (int, objects.Object) {
+	for i, el := range array.Elements {
+		ok, err := e.callPredicate(name, fn, el, env)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			return i, nil
+		}
+	}
+
+	return len(array.Elements), nil
+}
+
+// Calls a predicate function with the given element. The predicate must return a boolean.
+func (e *Evaluator) callPredicate(
+	name string, fn objects.Object, el objects.Object, env *objects.Storage,
+) (bool, objects.Object) {
+	res := e.applyFunction(fn, []objects.Object{el}, env)
+	if isError(res) {
+		return false, res
+	}
+
+	if res.Type() != objects.BOOL_OBJ {
+		return false, objects.NewTypeError(
+			"Predicate of '%s' must return a boolean. \n\tGot: %s", name, res.Type())
+	}
+
+	return res == true_obj, nil
+}
+
+// flat_map(arr, fn) calls "fn" with each element and concatenates the arrays it returns
+func builtinFlatMap(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("flat_map", args)
+	if err != nil {
+		return err
+	}
+
+	result := []objects.Object{}
+	for _, el := range array.Items() {
+		res := e.applyFunction(fn, []objects.Object{el}, env)
+		if isError(res) {
+			return res
+		}
+
+		mapped, ok := res.(*objects.Array)
+		if !ok {
+			return objects.NewTypeError(
+				"Function of 'flat_map' must return an array. \n\tGot: %s", describeObject(res))
+		}
+
+		result = append(result, mapped.Elements...)
+		if err := e.checkArraySize(int64(len(result))); err != nil {
+			return err
+		}
+	}
+
+	return &objects.Array{Elements: result}
+}
+
+// sort_by(arr, fn) returns a new array sorted ascending by the keys returned by "fn". Keys
+// must be numbers or strings, and elements with equal keys keep their original order.
+func builtinSortBy(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, fn, err := arrayAndFunctionArgs("sort_by", args)
+	if err != nil {
+		return err
+	}
+
+	elements := array.Items()
+	keys := make([]objects.Object, len(elements))
+	for i, el := range elements {
+		key := e.applyFunction(fn, []objects.Object{el}, env)
+		if isError(key) {
+			return key
+		}
+
+		switch key.(type) {
+		case *objects.Integer, *objects.Float, *objects.String:
+		default:
+			return objects.NewTypeError(
+				"Key of 'sort_by' must be an integer, float or string. \n\tGot: %s", describeObject(key))
+		}
+
+		if i > 0 && isNumber(key) != isNumber(keys[0]) {
+			return objects.NewTypeError("Cannot compare keys of 'sort_by'. \n\tGot: %s and %s",
+				describeObject(keys[0]), describeObject(key))
+		}
+
+		keys[i] = key
+	}
+
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return lessKey(keys[order[a]], keys[order[b]])
+	})
+
+	sorted := make([]objects.Object, len(elements))
+	for i, idx := range order {
+		sorted[i] = elements[idx]
+	}
+
+	return &objects.Array{Elements: sorted}
+}
+
+// zip_with(a, b, fn) returns an array with fn(a[i], b[i]) for every index of the shorter
+// array
+func builtinZipWith(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 3 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'zip_with'. Expected 3, got %d", len(args))
+	}
+
+	first, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'zip_with' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	second, ok := args[1].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"Second argument of 'zip_with' must be an array. \n\tGot: %s", args[1].Type())
+	}
+
+	fn := args[2]
+	if !isCallable(fn) {
+		return objects.NewTypeError(
+			"Third argument of 'zip_with' must be a function. \n\tGot: %s", fn.Type())
+	}
+
+	if f, ok := fn.(*objects.FunctionObject); ok && len(f.Parameters) != 2 {
+		return objects.NewArgumentError(
+			"Function of 'zip_with' must take 2 arguments. Got %d", len(f.Parameters))
+	}
+
+	length := min(len(first.Elements), len(second.Elements))
+	result := make([]objects.Object, length)
+	for i := 0; i < length; i++ {
+		res := e.applyFunction(fn, []objects.Object{first.Elements[i], second.Elements[i]}, env)
+		if isError(res) {
+			return res
+		}
+		result[i] = res
+	}
+
+	return &objects.Array{Elements: result}
+}
+
+// print(args...) writes the arguments separated by spaces, followed by a line break, to
+// the output of the evaluator
+func builtinPrint(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = e.Inspect(arg)
+	}
+
+	err := e.write(strings.Join(values, " ") + "\n")
+	if err == errOutputLimit {
+		return objects.NewLimitError("%s", err)
+	} else if err != nil {
+		return objects.NewIOError("%s", err)
+	}
+
+	return null_obj
+}
+
+// retry(n, fn) calls fn() up to n times, until it returns a value that is neither an
+// error nor null. That value is returned, or null if every attempt failed.
+func builtinRetry(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'retry'. Expected 2, got %d", len(args))
+	}
+
+	attempts, ok := args[0].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'retry' must be an integer. \n\tGot: %s", describeObject(args[0]))
+	}
+
+	fn := args[1]
+	if !isCallable(fn) {
+		return objects.NewTypeError(
+			"Second argument of 'retry' must be a function. \n\tGot: %s", fn.Type())
+	}
+
+	if f, ok := fn.(*objects.FunctionObject); ok && len(f.Parameters) != 0 {
+		return objects.NewArgumentError(
+			"Function of 'retry' must take no arguments. Got %d", len(f.Parameters))
+	}
+
+	for i := int64(0); i < attempts.Value; i++ {
+		res := e.applyFunction(fn, nil, env)
+		if res != nil && !isError(res) && res.Type() != objects.NULL_OBJ {
+			return res
+		}
+	}
+
+	return null_obj
+}
+
+func isNumber(obj objects.Object) bool {
+	switch obj.(type) {
+	case *objects.Integer, *objects.Float:
+		return true
+	}
+
+	return false
+}
+
+// Compares two numbers or two strings
+func lessKey(a, b objects.Object) bool {
+	switch a := a.(type) {
+	case *objects.String:
+		return a.Value < b.(*objects.String).Value
+	case *objects.Integer:
+		if b, ok := b.(*objects.Integer); ok {
+			return a.Value < b.Value
+		}
+	}
+
+	return toFloat(a) < toFloat(b)
+}
+
+func toFloat(obj objects.Object) float64 {
+	if integer, ok := obj.(*objects.Integer); ok {
+		return float64(integer.Value)
+	}
+
+	return obj.(*objects.Float).Value
+}
+
+// Validates the (array, function) arguments shared by many builtins
+func arrayAndFunctionArgs(name string, args []objects.Object) (*objects.Array, objects.Object, objects.Object) {
+	if len(args) != 2 {
+		return nil, nil, objects.NewArgumentError(
+			"Wrong number of arguments for '%s'. Expected 2, got %d", name, len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return nil, nil, objects.NewTypeError(
+			"First argument of '%s' must be an array. \n\tGot: %s", name, args[0].Type())
+	}
+
+	if !isCallable(args[1]) {
+		return nil, nil, objects.NewTypeError(
+			"Second argument of '%s' must be a function. \n\tGot: %s", name, args[1].Type())
+	}
+
+	return array, args[1], nil
+}
+
+// to_bool(x) converts a value to a boolean. Zero, empty strings, empty collections and
+// null are false. Booleans are returned unchanged.
+func builtinToBool(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'to_bool'. Expected 1, got %d", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *objects.Boolean:
+		return selectBoolObject(arg.Value)
+	case *objects.Integer:
+		return selectBoolObject(arg.Value != 0)
+	case *objects.String:
+		return selectBoolObject(arg.Value != "")
+	case *objects.Null:
+		return false_obj
+	case *objects.Array:
+		return selectBoolObject(len(arg.Elements) != 0)
+	case *objects.Hash:
+		return selectBoolObject(arg.Len() != 0)
+	}
+
+	return objects.NewTypeError("Cannot convert %s to a boolean", args[0].Type())
+}
+
+// range(end) or range(start, end) returns an array with the integers from "start"
+// (0 by default) up to, but not including, "end".
+func builtinRange(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	start, end, err := rangeBounds("range", args)
+	if err != nil {
+		return err
+	}
+
+	if end < start {
+		return &objects.Array{Elements: []objects.Object{}}
+	}
+
+	if err := e.checkArraySize(end - start); err != nil {
+		return err
+	}
+
+	elements := make([]objects.Object, 0, end-start)
+	for i := start; i < end; i++ {
+		elements = append(elements, &objects.Integer{Value: i})
+	}
+
+	return &objects.Array{Elements: elements}
+}
+
+// range_sum(end) or range_sum(start, end) returns the sum of the integers of
+// range(start, end), computed as an arithmetic series without building the array.
+func builtinRangeSum(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	start, end, err := rangeBounds("range_sum", args)
+	if err != nil {
+		return err
+	}
+
+	if end <= start {
+		return &objects.Integer{Value: 0}
+	}
+
+	// (end - start) * (start + end - 1) / 2, without overflowing the intermediate results
+	count := new(big.Int).Sub(big.NewInt(end), big.NewInt(start))
+	sum := new(big.Int).Add(big.NewInt(start), big.NewInt(end-1))
+	sum.Mul(sum, count).Quo(sum, big.NewInt(2))
+
+	if !sum.IsInt64() {
+		return objects.NewLimitError("result too large: the sum does not fit on an integer")
+	}
+
+	return &objects.Integer{Value: sum.Int64()}
+}
+
+// Validates the arguments of range and range_sum: (end) or (start, end)
+func rangeBounds(name string, args []objects.Object) (int64, int64, objects.Object) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, 0, objects.NewArgumentError(
+			"Wrong number of arguments for '%s'. Expected 1 or 2, got %d", name, len(args))
+	}
+
+	bounds := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*objects.Integer)
+		if !ok {
+			return 0, 0, objects.NewTypeError(
+				"Arguments of '%s' must be integers. \n\tGot: %s", name, arg.Type())
+		}
+		bounds[i] = integer.Value
+	}
+
+	if len(bounds) == 2 {
+		return bounds[0], bounds[1], nil
+	}
+
+	return 0, bounds[0], nil
+}
+
+// array_fill(n, value) returns an array with n times the given value. Arrays and hashes
+// are not copied, so every element is the same collection.
+func builtinArrayFill(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'array_fill'. Expected 2, got %d", len(args))
+	}
+
+	return e.fillArray("array_fill", args[0], args[1])
+}
+
+// make_array(n) returns an array of n nulls
+func builtinMakeArray(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'make_array'. Expected 1, got %d", len(args))
+	}
+
+	return e.fillArray("make_array", args[0], null_obj)
+}
+
+func (e *Evaluator) fillArray(name string, size objects.Object, value objects.Object) objects.Object {
+	n, ok := size.(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of '%s' must be an integer. \n\tGot: %s", name, describeObject(size))
+	}
+
+	if n.Value < 0 {
+		return objects.NewArgumentError("Size of '%s' cannot be negative. Got %d", name, n.Value)
+	}
+
+	if err := e.checkArraySize(n.Value); err != nil {
+		return err
+	}
+
+	elements := make([]objects.Object, n.Value)
+	for i := range elements {
+		elements[i] = value
+	}
+
+	return &objects.Array{Elements: elements}
+}
+
+// first(arr) returns the first element of the array, or null if it is empty
+func builtinFirst(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, err := arrayArg("first", args)
+	if err != nil {
+		return err
+	}
+
+	if len(array.Elements) == 0 {
+		return null_obj
+	}
+
+	return array.Elements[0]
+}
+
+// last(arr) returns the last element of the array, or null if it is empty
+func builtinLast(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, err := arrayArg("last", args)
+	if err != nil {
+		return err
+	}
+
+	if len(array.Elements) == 0 {
+		return null_obj
+	}
+
+	return array.Elements[len(array.Elements)-1]
+}
+
+// rest(arr) returns a new array with every element but the first one. Empty arrays
+// return an empty array.
+func builtinRest(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, err := arrayArg("rest", args)
+	if err != nil {
+		return err
+	}
+
+	if len(array.Elements) == 0 {
+		return &objects.Array{Elements: []objects.Object{}}
+	}
+
+	elements := make([]objects.Object, len(array.Elements)-1)
+	copy(elements, array.Elements[1:])
+
+	return &objects.Array{Elements: elements}
+}
+
+// init(arr) returns a new array with every element but the last one. Empty arrays
+// return an empty array.
+func builtinInit(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	array, err := arrayArg("init", args)
+	if err != nil {
+		return err
+	}
+
+	if len(array.Elements) == 0 {
+		return &objects.Array{Elements: []objects.Object{}}
+	}
+
+	elements := make([]objects.Object, len(array.Elements)-1)
+	copy(elements, array.Elements[:len(array.Elements)-1])
+
+	return &objects.Array{Elements: elements}
+}
+
+// Validates that the builtin recieved a single array argument
+func arrayArg(name string, args []objects.Object) (*objects.Array, objects.Object) {
+	if len(args) != 1 {
+		return nil, objects.NewArgumentError(
+			"Wrong number of arguments for '%s'. Expected 1, got %d", name, len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return nil, objects.NewTypeError(
+			"Argument of '%s' must be an array. \n\tGot: %s", name, args[0].Type())
+	}
+
+	return array, nil
+}
+
+// now() returns the current time as Unix nanoseconds
+func builtinNow(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 0 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'now'. Expected 0, got %d", len(args))
+	}
+
+	return &objects.Integer{Value: e.now().UnixNano()}
+}
+
+// repeat_string(s, n, sep) joins n copies of "s" with the optional separator "sep"
+func builtinRepeatString(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'repeat_string'. Expected 2 or 3, got %d", len(args))
+	}
+
+	str, ok := args[0].(*objects.String)
+	if !ok {
+		return objects.NewTypeError(
+			"First argument of 'repeat_string' must be a string. \n\tGot: %s", describeObject(args[0]))
+	}
+
+	times, ok := args[1].(*objects.Integer)
+	if !ok {
+		return objects.NewTypeError(
+			"Second argument of 'repeat_string' must be an integer. \n\tGot: %s", describeObject(args[1]))
+	}
+
+	sep := ""
+	if len(args) == 3 {
+		s, ok := args[2].(*objects.String)
+		if !ok {
+			return objects.NewTypeError(
+				"Third argument of 'repeat_string' must be a string. \n\tGot: %s", describeObject(args[2]))
+		}
+		sep = s.Value
+	}
+
+	return e.repeatString(str.Value, times.Value, sep)
+}
+
+// keys(h) returns the keys of a hash in insertion order
+func builtinKeys(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'keys'. Expected 1, got %d", len(args))
+	}
+
+	hash, ok := args[0].(objects.Ordered)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'keys' must be a hash. \n\tGot: %s", describeObject(args[0]))
+	}
+
+	return &objects.Array{Elements: hash.Keys()}
+}
+
+// entries(h) returns the [key, value] pairs of a hash in insertion order
+func builtinEntries(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'entries'. Expected 1, got %d", len(args))
+	}
+
+	hash, ok := args[0].(objects.Ordered)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'entries' must be a hash. \n\tGot: %s", describeObject(args[0]))
+	}
+
+	pairs := hash.Pairs()
+
+	entries := make([]objects.Object, len(pairs))
+	for i, pair := range pairs {
+		entries[i] = &objects.Array{Elements: []objects.Object{pair.Key, pair.Value}}
+	}
+
+	return &objects.Array{Elements: entries}
+}
+
+// merge(a, b, ...) returns a new hash with the pairs of every hash. Later hashes override
+// the keys of the earlier ones, which keep their original position.
+func builtinMerge(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	merged := objects.NewHash()
+
+	for i, arg := range args {
+		hash, ok := arg.(objects.Ordered)
+		if !ok {
+			return objects.NewTypeError(
+				"Argument %d of 'merge' must be a hash. \n\tGot: %s", i+1, describeObject(arg))
+		}
+
+		for _, pair := range hash.Pairs() {
+			merged.Set(pair.Key.(objects.Hashable), pair.Value)
+		}
+	}
+
+	return merged
+}
+
+// frequency(arr) returns a hash with the number of occurrences of every element, in the
+// order of their first occurrence
+func builtinFrequency(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'frequency'. Expected 1, got %d", len(args))
+	}
+
+	array, ok := args[0].(*objects.Array)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'frequency' must be an array. \n\tGot: %s", args[0].Type())
+	}
+
+	counts := objects.NewHash()
+	for _, el := range array.Elements {
+		key, ok := el.(objects.Hashable)
+		if !ok {
+			return objects.NewTypeError("Unusable as hash key: %s", el.Type())
+		}
+
+		count := int64(0)
+		if value, ok := counts.Get(key); ok {
+			count = value.(*objects.Integer).Value
+		}
+
+		counts.Set(key, &objects.Integer{Value: count + 1})
+	}
+
+	return counts
+}
@@ -0,0 +1,1225 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sl2.0/objects"
+)
+
+func TestBuiltinPartial(t *testing.T) {
+	tcase := `func digits(a, b, c) {
+		retorna a * 100 + b * 10 + c;
+	}
+	var withOne = partial(digits, 1);
+	withOne(2, 3)`
+
+	evaluated := parseAndEval(t, tcase)
+	if evaluated == nil {
+		return
+	}
+
+	testInteger(t, evaluated, 123)
+
+	evaluated = parseAndEval(t, "partial(2, 3)")
+	if evaluated == nil {
+		return
+	}
+
+	testError(t, evaluated, "First argument of 'partial' must be a function.")
+}
+
+func TestBuiltinCompose(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func inc(x) { retorna x + 1; }
+			func double(x) { retorna x * 2; }
+			var incThenDouble = compose(double, inc);
+			incThenDouble(5)`,
+			expected: 12,
+		},
+		{
+			tcase: `func inc(x) { retorna x + 1; }
+			func double(x) { retorna x * 2; }
+			var doubleThenInc = compose(inc, double);
+			doubleThenInc(5)`,
+			expected: 11,
+		},
+		{
+			tcase: `func inc(x) { retorna x + 1; }
+			func fail(x) { retorna x * true; }
+			var broken = compose(inc, fail);
+			broken(5)`,
+			expected: "Expected right value of '*' to be INTEGER.",
+		},
+		{
+			tcase: `func add(a, b) { retorna a + b; }
+			compose(add)`,
+			expected: "Argument 1 of 'compose' must be a single argument function.",
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		switch expected := tc.expected.(type) {
+		case int:
+			testInteger(t, evaluated, int64(expected))
+		case string:
+			testError(t, evaluated, expected)
+		}
+	}
+}
+
+func TestBuiltinCurry(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func digits(a, b, c) { retorna a * 100 + b * 10 + c; }
+			curry(digits)(1)(2)(3)`,
+			expected: 123,
+		},
+		{
+			tcase: `func digits(a, b, c) { retorna a * 100 + b * 10 + c; }
+			var conUno = curry(digits)(1);
+			var conDos = conUno(2);
+			conDos(3) + conUno(4)(5)`,
+			expected: 123 + 145,
+		},
+		{
+			tcase:    `func add(a, b) { retorna a + b; } curry(add)(1)(2) == add(1, 2)`,
+			expected: true,
+		},
+		{
+			tcase:    `func inc(x) { retorna x + 1; } curry(inc)(1)`,
+			expected: 2,
+		},
+		{
+			tcase:    `func add(a, b) { retorna a + b; } curry(add)(1, 2)`,
+			expected: errorMessage("Wrong number of arguments for curried function. Expected 1, got 2"),
+		},
+		{
+			tcase:    `curry(first)`,
+			expected: errorMessage("Argument of 'curry' must be a user defined function."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinCharCode(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `char_code("A")`, expected: 65},
+		{tcase: `from_char_code(65)`, expected: "A"},
+		{tcase: `from_char_code(char_code("A"))`, expected: "A"},
+		{tcase: `char_code(from_char_code(65))`, expected: 65},
+		{tcase: `char_code("AB")`, expected: errorMessage("Argument of 'char_code' must be a single character.")},
+		{tcase: `from_char_code(-1)`, expected: errorMessage("Invalid code point: -1")},
+		{tcase: `from_char_code(1114112)`, expected: errorMessage("Invalid code point: 1114112")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinUnique(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected string
+	}{
+		{tcase: `unique([1, 1, 2, 3, 3, 3])`, expected: `[1, 2, 3]`},
+		{tcase: `unique([3, 1, 3, 2, 1])`, expected: `[3, 1, 2]`},
+		{tcase: `unique(["a", 1, "a", true, true])`, expected: `["a", 1, true]`},
+		{tcase: `unique([[1, 2], [1, 2], {"a": 1}, {"a": 1}, [2]])`, expected: `[[1, 2], {"a": 1}, [2]]`},
+		{tcase: `unique([])`, expected: `[]`},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testInspect(t, evaluated, objects.ARRAY_OBJ, tc.expected)
+	}
+
+	evaluated := parseAndEval(t, `unique(1)`)
+	if evaluated != nil {
+		testError(t, evaluated, "Argument of 'unique' must be an array.")
+	}
+}
+
+func TestBuiltinRuns(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `dedup_consecutive([1, 1, 2, 2, 2, 1])`, expected: `[1, 2, 1]`},
+		{tcase: `run_length([1, 1, 2, 2, 2, 1])`, expected: `[[1, 2], [2, 3], [1, 1]]`},
+		{tcase: `dedup_consecutive([[1], [1], {"a": 1}, {"a": 1}, "x"])`, expected: `[[1], {"a": 1}, "x"]`},
+		{tcase: `run_length(["a", "a", 1, "1"])`, expected: `[["a", 2], [1, 1], ["1", 1]]`},
+		{tcase: `dedup_consecutive([])`, expected: `[]`},
+		{tcase: `run_length([])`, expected: `[]`},
+		{tcase: `run_length("aab")`, expected: errorMessage("Argument of 'run_length' must be an array.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinSlice(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `slice([1, 2, 3, 4], 1, 3)`, expected: `[2, 3]`},
+		{tcase: `slice([1, 2, 3, 4], 0, 4)`, expected: `[1, 2, 3, 4]`},
+		{tcase: `slice([1, 2, 3, 4], 2, 2)`, expected: `[]`},
+		{tcase: `var arr = [1, 2, 3]; slice(arr, 0, 1); arr`, expected: `[1, 2, 3]`},
+		{tcase: `slice([1, 2, 3], 2, 4)`, expected: errorMessage("Index out of range: 4")},
+		{tcase: `slice([1, 2, 3], -1, 2)`, expected: errorMessage("Index out of range: -1")},
+		{tcase: `slice([1, 2, 3], 2, 1)`, expected: errorMessage("Index out of range: 1")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinChunk(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `chunk([1, 2, 3, 4, 5, 6, 7], 3)`, expected: `[[1, 2, 3], [4, 5, 6], [7]]`},
+		{tcase: `last(chunk([1, 2, 3, 4, 5, 6, 7], 3))`, expected: `[7]`},
+		{tcase: `chunk([1, 2, 3, 4], 2)`, expected: `[[1, 2], [3, 4]]`},
+		{tcase: `chunk([1, 2], 10)`, expected: `[[1, 2]]`},
+		{tcase: `chunk([], 3)`, expected: `[]`},
+		{
+			// the chunks are copies
+			tcase: `var arr = [1, 2, 3];
+			var partes = chunk(arr, 2);
+			partes[0][0] = 9;
+			arr`,
+			expected: `[1, 2, 3]`,
+		},
+		{tcase: `chunk([1, 2], 0)`, expected: errorMessage("Size of 'chunk' must be greater than 0. Got 0")},
+		{tcase: `chunk([1, 2], -1)`, expected: errorMessage("Size of 'chunk' must be greater than 0. Got -1")},
+		{tcase: `chunk("abc", 1)`, expected: errorMessage("First argument of 'chunk' must be an array.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinSwap(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `var arr = [1, 2, 3]; swap(arr, 0, 2); arr`, expected: `[3, 2, 1]`},
+		{tcase: `var arr = [1, 2]; swap(arr, 1, 1); arr`, expected: `[1, 2]`},
+		{tcase: `swap([1, 2], 0, 1)`, expected: nil},
+		{
+			tcase: `func ordenar(arr, n) {
+				repetir i en range(n) {
+					repetir j en range(n - 1 - i) {
+						si (arr[j] > arr[j + 1]) {
+							swap(arr, j, j + 1);
+						}
+					}
+				}
+				retorna arr;
+			}
+			ordenar([5, 1, 4, 2, 3], 5)`,
+			expected: `[1, 2, 3, 4, 5]`,
+		},
+		{tcase: `swap([1, 2], 0, 2)`, expected: errorMessage("Index out of range: 2")},
+		{tcase: `swap([1, 2], -1, 0)`, expected: errorMessage("Index out of range: -1")},
+		{tcase: `swap([1, 2], 0, "1")`, expected: errorMessage("Indexes of 'swap' must be integers.")},
+		{tcase: `swap("ab", 0, 1)`, expected: errorMessage("First argument of 'swap' must be an array.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinSplice(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase:    `var arr = [1, 2, 3, 4, 5]; splice(arr, 1, 2, "a", "b", "c")`,
+			expected: `[2, 3]`,
+		},
+		{
+			tcase:    `var arr = [1, 2, 3, 4, 5]; splice(arr, 1, 2, "a", "b", "c"); arr`,
+			expected: `[1, "a", "b", "c", 4, 5]`,
+		},
+		{
+			tcase:    `var arr = [1, 2]; splice(arr, 2, 0, 3); arr`,
+			expected: `[1, 2, 3]`,
+		},
+		{
+			tcase:    `var arr = [1, 2]; splice(arr, 3, 0)`,
+			expected: errorMessage("Index out of range: 3"),
+		},
+		{
+			tcase:    `var arr = [1, 2]; splice(arr, 1, 2)`,
+			expected: errorMessage("Invalid number of elements to remove: 2"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinEquals(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `equals(1, 1)`, expected: true},
+		{tcase: `equals(1, "1")`, expected: false},
+		{tcase: `equals("a", "a")`, expected: true},
+		{tcase: `equals(true, false)`, expected: false},
+		{tcase: `equals(null, null)`, expected: true},
+		{tcase: `equals([1, [2, 3]], [1, [2, 3]])`, expected: true},
+		{tcase: `equals([1, 2], [2, 1])`, expected: false},
+		{tcase: `equals([1, 2], [1, 2, 3])`, expected: false},
+		{tcase: `equals({"a": 1, "b": [2]}, {"b": [2], "a": 1})`, expected: true},
+		{tcase: `equals({"a": 1}, {"a": 2})`, expected: false},
+		{tcase: `equals({"a": 1}, {"b": 1})`, expected: false},
+		{tcase: `equals(1)`, expected: errorMessage("Wrong number of arguments for 'equals'.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestEqualsMatchesOperator(t *testing.T) {
+	pairs := [][2]string{
+		{`1`, `from_json("1.0")`},
+		{`from_json("1.0")`, `1`},
+		{`from_json("1.5")`, `1`},
+		{`from_json("2.5")`, `from_json("2.5")`},
+		{`1`, `1`},
+		{`1`, `2`},
+		{`null`, `from_json("0.0")`},
+	}
+
+	for _, pair := range pairs {
+		a, b := pair[0], pair[1]
+		tcase := fmt.Sprintf("equals(%s, %s) == (%s == %s)", a, b, a, b)
+
+		evaluated := parseAndEval(t, tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		if evaluated != true_obj {
+			t.Errorf("Expected equals and '==' to agree on %s and %s", a, b)
+		}
+	}
+
+	evaluated := parseAndEval(t, `equals([1, {"a": 2}], [from_json("1.0"), {"a": from_json("2.0")}])`)
+	if evaluated != nil {
+		testBool(t, evaluated, true)
+	}
+}
+
+func TestBuiltinFlatMap(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func copies(x) {
+				si (x == 0) { retorna []; }
+				si (x == 1) { retorna [x]; }
+				retorna [x, x];
+			}
+			flat_map([0, 1, 2, 0, 1], copies)`,
+			expected: `[1, 2, 2, 1]`,
+		},
+		{
+			tcase: `func wrap(x) { retorna [[x]]; }
+			flat_map(["a", "b"], wrap)`,
+			expected: `[["a"], ["b"]]`,
+		},
+		{
+			tcase: `func wrap(x) { retorna [x]; }
+			flat_map([], wrap)`,
+			expected: `[]`,
+		},
+		{
+			tcase: `func same(x) { retorna x; }
+			flat_map([1], same)`,
+			expected: errorMessage("Function of 'flat_map' must return an array."),
+		},
+		{
+			tcase:    `flat_map([1], 2)`,
+			expected: errorMessage("Second argument of 'flat_map' must be a function."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinDeepEqual(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase:    `deep_equal({"a": [1, {"b": [2, 3]}], "c": null}, {"c": null, "a": [1, {"b": [2, 3]}]})`,
+			expected: true,
+		},
+		{
+			tcase:    `deep_equal([[1, [2, [3]]], "x"], [[1, [2, [3]]], "x"])`,
+			expected: true,
+		},
+		{
+			tcase:    `deep_equal({"a": [1, {"b": [2, 3]}]}, {"a": [1, {"b": [2, 4]}]})`,
+			expected: false,
+		},
+		{
+			tcase:    `deep_equal([[1, [2, [3]]]], [[1, [2, ["3"]]]])`,
+			expected: false,
+		},
+		{
+			tcase:    `deep_equal([{"a": 1}], [{"a": 1, "b": 2}])`,
+			expected: false,
+		},
+		{
+			// collections that contain themselves
+			tcase:    `var h = {}; h["self"] = h; deep_equal(h, h)`,
+			expected: true,
+		},
+		{
+			tcase: `var a = {"n": 1}; a["self"] = a;
+			var b = {"n": 1}; b["self"] = b;
+			deep_equal(a, b)`,
+			expected: true,
+		},
+		{
+			tcase: `var a = {"n": 1}; a["self"] = a;
+			var b = {"n": 2}; b["self"] = b;
+			deep_equal(a, b)`,
+			expected: false,
+		},
+		{
+			tcase:    `var arr = [1]; arr[0] = arr; equals(arr, [arr])`,
+			expected: true,
+		},
+		{
+			tcase:    `deep_equal([])`,
+			expected: errorMessage("Wrong number of arguments for 'deep_equal'."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinFind(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func big(x) { retorna x > 10; }
+			find([3, 12, 25], big)`,
+			expected: 12,
+		},
+		{
+			tcase: `func big(x) { retorna x > 10; }
+			find_index([3, 12, 25], big)`,
+			expected: 1,
+		},
+		{
+			tcase: `func big(x) { retorna x > 100; }
+			find([3, 12, 25], big)`,
+			expected: nil,
+		},
+		{
+			tcase: `func big(x) { retorna x > 100; }
+			find_index([3, 12, 25], big)`,
+			expected: -1,
+		},
+		{
+			tcase: `func broken(x) { retorna x + true; }
+			find([1], broken)`,
+			expected: errorMessage("Expected right value of '+' to be INTEGER."),
+		},
+		{
+			tcase: `func notBool(x) { retorna x; }
+			find_index([1], notBool)`,
+			expected: errorMessage("Predicate of 'find_index' must return a boolean."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinIndexAll(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `index_all([1, 2, 1, 3, 1], 1)`, expected: `[0, 2, 4]`},
+		{tcase: `index_all([[1], 2, [1]], [1])`, expected: `[0, 2]`},
+		{tcase: `index_all([1, 2], 5)`, expected: `[]`},
+		{tcase: `index_all("aaaa", "aa")`, expected: `[0, 1, 2]`},
+		{tcase: `index_all("añoaño", "o")`, expected: `[2, 5]`},
+		{tcase: `index_all("hola", "x")`, expected: `[]`},
+		{tcase: `index_all("hola", "")`, expected: errorMessage("Cannot search an empty string with 'index_all'")},
+		{tcase: `index_all("hola", 1)`, expected: errorMessage("Second argument of 'index_all' must be a string")},
+		{tcase: `index_all(1, 1)`, expected: errorMessage("First argument of 'index_all' must be an array or a string.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinTakeDropWhile(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `take_while([1, 2, 3, 0, 4], func(x) { retorna x < 3; })`, expected: `[1, 2]`},
+		{tcase: `drop_while([1, 2, 3, 0, 4], func(x) { retorna x < 3; })`, expected: `[3, 0, 4]`},
+		{tcase: `take_while([1, 2], func(x) { retorna true; })`, expected: `[1, 2]`},
+		{tcase: `drop_while([1, 2], func(x) { retorna true; })`, expected: `[]`},
+		{tcase: `take_while([], func(x) { retorna true; })`, expected: `[]`},
+		{
+			// the predicate stops being called after the first false
+			tcase: `var llamadas = 0;
+			take_while([1, 5, 1, 1], func(x) { llamadas = llamadas + 1; retorna x < 3; });
+			[llamadas]`,
+			expected: `[2]`,
+		},
+		{
+			tcase:    `take_while([1, 2], func(x) { retorna x * true; })`,
+			expected: errorMessage("Expected right value of '*' to be INTEGER."),
+		},
+		{
+			tcase:    `drop_while([1, 2], func(x) { retorna x; })`,
+			expected: errorMessage("Predicate of 'drop_while' must return a boolean."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinToBool(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `to_bool(0)`, expected: false},
+		{tcase: `to_bool(-3)`, expected: true},
+		{tcase: `to_bool("")`, expected: false},
+		{tcase: `to_bool("false")`, expected: true},
+		{tcase: `to_bool(null)`, expected: false},
+		{tcase: `to_bool(true)`, expected: true},
+		{tcase: `to_bool(false)`, expected: false},
+		{tcase: `to_bool([])`, expected: false},
+		{tcase: `to_bool([0])`, expected: true},
+		{tcase: `to_bool({})`, expected: false},
+		{tcase: `to_bool({"a": 1})`, expected: true},
+		{tcase: `to_bool(to_bool)`, expected: errorMessage("Cannot convert BUILTIN to a boolean")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinRange(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected string
+	}{
+		{tcase: `range(4)`, expected: `[0, 1, 2, 3]`},
+		{tcase: `range(2, 5)`, expected: `[2, 3, 4]`},
+		{tcase: `range(0)`, expected: `[]`},
+		{tcase: `range(5, 2)`, expected: `[]`},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testInspect(t, evaluated, objects.ARRAY_OBJ, tc.expected)
+	}
+}
+
+func TestBuiltinArrayFill(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `array_fill(5, 0)`, expected: `[0, 0, 0, 0, 0]`},
+		{tcase: `array_fill(2, "a")`, expected: `["a", "a"]`},
+		{tcase: `array_fill(0, 1)`, expected: `[]`},
+		{tcase: `make_array(3)`, expected: `[null, null, null]`},
+		{
+			// collections are shared between the elements
+			tcase: `var filas = array_fill(2, [0]);
+			filas[0][0] = 1;
+			filas`,
+			expected: `[[1], [1]]`,
+		},
+		{tcase: `array_fill(-1, 0)`, expected: errorMessage("Size of 'array_fill' cannot be negative. Got -1")},
+		{tcase: `make_array(-2)`, expected: errorMessage("Size of 'make_array' cannot be negative. Got -2")},
+		{tcase: `make_array("3")`, expected: errorMessage("First argument of 'make_array' must be an integer.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinListAccessors(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `first([1, 2, 3])`, expected: 1},
+		{tcase: `last([1, 2, 3])`, expected: 3},
+		{tcase: `rest([1, 2, 3])`, expected: `[2, 3]`},
+		{tcase: `init([1, 2, 3])`, expected: `[1, 2]`},
+		{tcase: `first([])`, expected: nil},
+		{tcase: `last([])`, expected: nil},
+		{tcase: `rest([])`, expected: `[]`},
+		{tcase: `init([])`, expected: `[]`},
+		{tcase: `first("abc")`, expected: errorMessage("Argument of 'first' must be an array.")},
+		{
+			tcase: `func sum(arr) {
+				si (!to_bool(arr)) {
+					retorna 0;
+				}
+				retorna first(arr) + sum(rest(arr));
+			}
+			sum([1, 2, 3, 4])`,
+			expected: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestIntegerTimesMethod(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `var indexes = [];
+			func collect(i) { splice(indexes, 0, 0, i * 10); }
+			5.times(collect);
+			indexes`,
+			expected: `[40, 30, 20, 10, 0]`,
+		},
+		{
+			tcase: `var n = 3;
+			var seen = [];
+			func collect(i) { splice(seen, 0, 0, i); }
+			n.times(collect);
+			n.times(collect);
+			seen`,
+			expected: `[2, 1, 0, 2, 1, 0]`,
+		},
+		{
+			tcase: `func fail(i) { retorna i + "a"; }
+			2.times(fail)`,
+			expected: errorMessage("Expected right value of '+' to be INTEGER."),
+		},
+		{
+			tcase:    `2.times(3)`,
+			expected: errorMessage("Argument of 'times' must be a function."),
+		},
+		{
+			tcase:    `2.missing`,
+			expected: errorMessage("Cannot access member 'missing' of INTEGER"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinNow(t *testing.T) {
+	fixed := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `now()`, expected: fixed.UnixNano()},
+		{tcase: `var start = now(); now() - start`, expected: 0},
+		{tcase: `now(1)`, expected: errorMessage("Wrong number of arguments for 'now'.")},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		ev.SetClock(func() time.Time { return fixed })
+
+		testObject(t, ev.EvalProgram(objects.NewStorage()), tc.expected)
+	}
+}
+
+func TestBuiltinPrint(t *testing.T) {
+	tcase := `print("hola", 1, [1, "a"], null);
+	print();
+	print({"x": true})`
+
+	ev := NewFromInput(tcase)
+	if ev == nil {
+		t.Fatalf("Parsing errors on: %s", tcase)
+	}
+
+	var output strings.Builder
+	ev.SetOutput(&output)
+
+	testObject(t, ev.EvalProgram(objects.NewStorage()), nil)
+
+	expected := "hola 1 [1, \"a\"] null\n\n{\"x\": true}\n"
+	if output.String() != expected {
+		t.Errorf("Expected output %q. Got %q", expected, output.String())
+	}
+}
+
+func TestBuiltinRepeatString(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `repeat_string("ab", 3, "-")`, expected: "ab-ab-ab"},
+		{tcase: `repeat_string("ab", 3)`, expected: "ababab"},
+		{tcase: `repeat_string("ab", 1, ", ")`, expected: "ab"},
+		{tcase: `repeat_string("ab", 0, "-")`, expected: ""},
+		{tcase: `repeat_string("", 3, "/")`, expected: "//"},
+		{
+			tcase:    `repeat_string("ab", -1)`,
+			expected: errorMessage("Cannot repeat a string a negative number of times: -1"),
+		},
+		{
+			tcase:    `repeat_string(1, 2)`,
+			expected: errorMessage("First argument of 'repeat_string' must be a string."),
+		},
+		{
+			tcase:    `repeat_string("a", 2, 3)`,
+			expected: errorMessage("Third argument of 'repeat_string' must be a string."),
+		},
+		{
+			tcase:    `repeat_string("a")`,
+			expected: errorMessage("Wrong number of arguments for 'repeat_string'."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinJson(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase:    `to_json({"b": [1, "dos", true, null], "a": {"x": -3}, 1: false})`,
+			expected: `{"b":[1,"dos",true,null],"a":{"x":-3},"1":false}`,
+		},
+		{tcase: `to_json("<a href=\"x\">\n")`, expected: `"<a href=\"x\">\n"`},
+		{tcase: `to_json(from_json("[1.5, 2.0, 1e3]"))`, expected: `[1.5,2.0,1000.0]`},
+		{
+			// round trip keeping the order of the keys
+			tcase: `var data = {"z": [1, [2, {"y": null}]], "a": {"b": {"c": "d"}}, "n": false};
+			deep_equal(from_json(to_json(data)), data)`,
+			expected: true,
+		},
+		{
+			tcase:    `to_json(from_json(" {\"z\": [1, {\"y\": []}], \"a\": {}} "))`,
+			expected: `{"z":[1,{"y":[]}],"a":{}}`,
+		},
+		{tcase: `from_json("[1, 2")`, expected: errorMessage("Invalid JSON: unexpected end of JSON input")},
+		{tcase: `from_json("[1 2]")`, expected: errorMessage("Invalid JSON:")},
+		{tcase: `from_json("{} []")`, expected: errorMessage("Invalid JSON: unexpected data after the JSON value")},
+		{tcase: `from_json("")`, expected: errorMessage("Invalid JSON: unexpected end of JSON input")},
+		{
+			tcase: `func f() {}
+			to_json([f])`,
+			expected: errorMessage("Cannot serialize to JSON: unsupported type FUNCTION"),
+		},
+		{tcase: `from_json(1)`, expected: errorMessage("Argument of 'from_json' must be a string.")},
+		{
+			tcase:    `var a = [1]; splice(a, 0, 0, a); to_json(a)`,
+			expected: errorMessage("Cannot serialize to JSON: the array contains itself"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinSortBy(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func longitud(s) {
+				var n = 0;
+				repetir c en s {
+					var n = n + 1;
+				}
+				retorna n;
+			}
+			var words = ["pera", "kiwi", "banana", "uva", "mango", "sol"];
+			sort_by(words, longitud)`,
+			expected: `["uva", "sol", "pera", "kiwi", "mango", "banana"]`,
+		},
+		{
+			tcase: `func longitud(s) {
+				var n = 0;
+				repetir c en s {
+					var n = n + 1;
+				}
+				retorna n;
+			}
+			var words = ["pera", "uva"];
+			sort_by(words, longitud);
+			words`,
+			expected: `["pera", "uva"]`,
+		},
+		{
+			tcase: `func id(x) { retorna x; }
+			sort_by(["b", "c", "a"], id)`,
+			expected: `["a", "b", "c"]`,
+		},
+		{
+			tcase: `func neg(x) { retorna -x; }
+			sort_by([2, 3, 1], neg)`,
+			expected: `[3, 2, 1]`,
+		},
+		{
+			tcase: `func id(x) { retorna x; }
+			sort_by([1, "a"], id)`,
+			expected: errorMessage("Cannot compare keys of 'sort_by'."),
+		},
+		{
+			tcase: `func id(x) { retorna x; }
+			sort_by([true], id)`,
+			expected: errorMessage("Key of 'sort_by' must be an integer, float or string."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinEntries(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `entries({"b": 2, "a": [1], 3: true})`, expected: `[["b", 2], ["a", [1]], [3, true]]`},
+		{tcase: `entries({})`, expected: `[]`},
+		{
+			tcase: `var total = 0;
+			repetir par en entries({"x": 1, "y": 2}) {
+				var total = total + par[1];
+			}
+			total`,
+			expected: 3,
+		},
+		{tcase: `entries([1, 2])`, expected: errorMessage("Argument of 'entries' must be a hash.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinMerge(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `merge({"a": 1, "b": 2}, {"b": 3, "c": 4})`, expected: `{"a": 1, "b": 3, "c": 4}`},
+		{tcase: `merge({1: "x"}, {}, {2: "y"}, {1: "z"})`, expected: `{1: "z", 2: "y"}`},
+		{tcase: `merge()`, expected: `{}`},
+		{
+			// the arguments are not modified
+			tcase: `var a = {"a": 1};
+			var b = {"a": 2, "b": 2};
+			merge(a, b);
+			[a, b]`,
+			expected: `[{"a": 1}, {"a": 2, "b": 2}]`,
+		},
+		{tcase: `merge({}, [1])`, expected: errorMessage("Argument 2 of 'merge' must be a hash.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinFrequency(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `frequency(["a", "b", "a", "c", "a"])`, expected: `{"a": 3, "b": 1, "c": 1}`},
+		{
+			// the counts do not depend on the order of the keys
+			tcase: `var f = frequency(["c", "a", "b", "a", "a"]);
+			[f["a"], f["b"], f["c"]]`,
+			expected: `[3, 1, 1]`,
+		},
+		{tcase: `frequency([1, "1", true, 1])`, expected: `{1: 2, "1": 1, true: 1}`},
+		{tcase: `frequency([])`, expected: `{}`},
+		{tcase: `frequency([1, [2]])`, expected: errorMessage("Unusable as hash key: ARRAY")},
+		{tcase: `frequency("aab")`, expected: errorMessage("Argument of 'frequency' must be an array.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinRangeSum(t *testing.T) {
+	sumRange := `func sumRange(start, end) {
+		var total = 0;
+		repetir n en range(start, end) {
+			total = total + n;
+		}
+		retorna total;
+	}
+	`
+
+	bounds := [][2]int{{0, 0}, {0, 1}, {0, 10}, {3, 7}, {-5, 5}, {-10, -3}, {4, 2}, {0, 1001}}
+	for _, b := range bounds {
+		expected := parseAndEval(t, fmt.Sprintf("%s sumRange(%d, %d)", sumRange, b[0], b[1]))
+		if expected == nil {
+			continue
+		}
+
+		evaluated := parseAndEval(t, fmt.Sprintf("range_sum(%d, %d)", b[0], b[1]))
+		if evaluated == nil {
+			continue
+		}
+
+		if !objects.Equals(evaluated, expected) {
+			t.Errorf("range_sum(%d, %d): expected %s. Got %s",
+				b[0], b[1], expected.Inspect(), evaluated.Inspect())
+		}
+	}
+
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `range_sum(100)`, expected: 4950},
+		{tcase: `range_sum(1000000000)`, expected: 499999999500000000},
+		{tcase: `range_sum(10000000000)`, expected: errorMessage("result too large")},
+		{tcase: `range_sum("a")`, expected: errorMessage("Arguments of 'range_sum' must be integers.")},
+		{tcase: `range_sum()`, expected: errorMessage("Wrong number of arguments for 'range_sum'.")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func BenchmarkRangeSum(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		input string
+	}{
+		{name: "closed form", input: `range_sum(10000)`},
+		{
+			name: "naive",
+			input: `var total = 0;
+			repetir n en range(10000) {
+				total = total + n;
+			}
+			total`,
+		},
+	}
+
+	for _, bm := range benchmarks {
+		ev := NewFromInput(bm.input)
+		if ev == nil {
+			b.Fatalf("Parsing errors on: %s", bm.input)
+		}
+
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ev.EvalProgram(objects.NewStorage())
+			}
+		})
+	}
+}
+
+func TestBuiltinZipWith(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `func sumar(a, b) { retorna a + b; }
+			zip_with([1, 2, 3], [10, 20, 30], sumar)`,
+			expected: `[11, 22, 33]`,
+		},
+		{
+			tcase: `func par(a, b) { retorna [a, b]; }
+			zip_with([1, 2, 3], ["a"], par)`,
+			expected: `[[1, "a"]]`,
+		},
+		{
+			tcase:    `zip_with([], [1], equals)`,
+			expected: `[]`,
+		},
+		{
+			tcase: `func sumar(a, b) { retorna a + b; }
+			zip_with([1, 2], [1, "b"], sumar)`,
+			expected: errorMessage("Expected right value of '+' to be INTEGER."),
+		},
+		{
+			tcase: `func uno(a) { retorna a; }
+			zip_with([1], [2], uno)`,
+			expected: errorMessage("Function of 'zip_with' must take 2 arguments. Got 1"),
+		},
+		{
+			tcase:    `zip_with([1], 2, equals)`,
+			expected: errorMessage("Second argument of 'zip_with' must be an array."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestBuiltinRetry(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			// fails with null, then with an error and succeeds on the third attempt
+			tcase: `var intentos = 0;
+			func consultar() {
+				intentos = intentos + 1;
+				si (intentos == 1) { retorna null; }
+				si (intentos == 2) { retorna 1 * true; }
+				retorna "listo";
+			}
+			[retry(5, consultar), intentos]`,
+			expected: `["listo", 3]`,
+		},
+		{
+			tcase: `var intentos = 0;
+			var res = retry(4, func() { intentos = intentos + 1; retorna null; });
+			[res, intentos]`,
+			expected: `[null, 4]`,
+		},
+		{
+			tcase:    `retry(0, func() { retorna 1; })`,
+			expected: nil,
+		},
+		{
+			tcase:    `retry(3, func(x) { retorna x; })`,
+			expected: errorMessage("Function of 'retry' must take no arguments. Got 1"),
+		},
+		{
+			tcase:    `retry("3", func() { retorna 1; })`,
+			expected: errorMessage("First argument of 'retry' must be an integer."),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		if inspect, ok := tc.expected.(string); ok {
+			testInspect(t, evaluated, objects.ARRAY_OBJ, inspect)
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
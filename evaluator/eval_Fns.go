@@ -1,6 +1,10 @@
 package evaluator
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/sl2.0/ast"
 	"github.com/sl2.0/objects"
 )
@@ -13,31 +17,57 @@ func (e *Evaluator) evalPrefix(exp *ast.PrefixExpression, env *objects.Storage)
 		return e.evalMinusPrefix(exp, env)
 	}
 
-	return objects.NewError("Prefix operation not supported: %s", exp.Operator)
+	return objects.NewTypeError("Prefix operation not supported: %s", exp.Operator)
 }
 
 func (e *Evaluator) evalInfix(exp *ast.InfixExpression, env *objects.Storage) objects.Object {
-	evalLeft := e.eval(exp.Left, env)
+	left := e.eval(exp.Left, env)
+	if isError(left) {
+		return left
+	}
 
-	switch evalLeft.Type() {
-	case objects.INTEGER_OBJ:
-		return e.evalArithmeticOperations(exp, env)
-	case objects.BOOL_OBJ:
-		return e.evalBooleanExpression(exp, env)
-	case objects.STRING_OBJ:
-		return e.evalStringExpression(exp, env)
+	right := e.eval(exp.Right, env)
+	if isError(right) {
+		return right
 	}
 
-	return objects.NewError("Not supported infix operation: %s", exp.Operator)
+	// null can be compared for equality against any value
+	if left.Type() == objects.NULL_OBJ || right.Type() == objects.NULL_OBJ {
+		switch exp.Operator {
+		case "==":
+			return selectBoolObject(objects.Equals(left, right))
+		case "!=":
+			return selectBoolObject(!objects.Equals(left, right))
+		}
+	}
+
+	switch left := left.(type) {
+	case *objects.Integer:
+		if right.Type() == objects.FLOAT_OBJ {
+			return e.evalFloatComparison(exp.Operator, left, right)
+		}
+		return e.evalArithmeticOperations(exp.Operator, left, right)
+	case *objects.Float:
+		return e.evalFloatComparison(exp.Operator, left, right)
+	case *objects.Boolean:
+		return e.evalBooleanExpression(exp.Operator, left, right)
+	case *objects.String:
+		return e.evalStringExpression(exp.Operator, left, right)
+	}
+
+	return objects.NewTypeError("Not supported infix operation for %s: %s", left.Type(), exp.Operator)
 }
 
 func (e *Evaluator) evalBangOperator(exp *ast.PrefixExpression, env *objects.Storage) objects.Object {
 	value := e.eval(exp.Right, env)
+	if isError(value) {
+		return value
+	}
 
 	if value.Type() != objects.BOOL_OBJ {
-		return objects.NewError(
-			"Expected boolean expression for '!' operator. \n\tGot: %v",
-			value.Inspect())
+		return objects.NewTypeError(
+			"Expected BOOL expression for '!' operator.\n\tGot: %s",
+			describeObject(value))
 	}
 
 	// we can compare object references because we have static true and false
@@ -50,11 +80,14 @@ func (e *Evaluator) evalBangOperator(exp *ast.PrefixExpression, env *objects.Sto
 
 func (e *Evaluator) evalMinusPrefix(exp *ast.PrefixExpression, env *objects.Storage) objects.Object {
 	value := e.eval(exp.Right, env)
+	if isError(value) {
+		return value
+	}
 
 	if value.Type() != objects.INTEGER_OBJ {
-		return objects.NewError(
-			"Expected integer expression for '-' operator. \n\tGot: %v",
-			value.Inspect())
+		return objects.NewTypeError(
+			"Expected INTEGER expression for '-' operator.\n\tGot: %s",
+			describeObject(value))
 	}
 
 	res := value.(*objects.Integer)
@@ -62,72 +95,68 @@ func (e *Evaluator) evalMinusPrefix(exp *ast.PrefixExpression, env *objects.Stor
 	return &objects.Integer{Value: -res.Value}
 }
 
-func (e *Evaluator) evalBooleanExpression(exp *ast.InfixExpression, env *objects.Storage) objects.Object {
-	left := e.eval(exp.Left, env).(*objects.Boolean)
-
-	evalRight := e.eval(exp.Right, env)
-
+func (e *Evaluator) evalBooleanExpression(operator string, left *objects.Boolean, evalRight objects.Object) objects.Object {
 	if evalRight.Type() != objects.BOOL_OBJ {
-		return objects.NewError(
-			"Expected right value to be a boolean.\n\tGot: %v",
-			evalRight.Inspect())
+		return objects.NewTypeError(
+			"Expected right value of '%s' to be BOOL.\n\tGot: %s",
+			operator, describeObject(evalRight))
 	}
 
 	right := evalRight.(*objects.Boolean)
 
-	switch exp.Operator {
+	switch operator {
 	case "==":
-		return &objects.Boolean{Value: left.Value == right.Value}
+		return selectBoolObject(left.Value == right.Value)
 	case "!=":
-		return &objects.Boolean{Value: left.Value != right.Value}
+		return selectBoolObject(left.Value != right.Value)
 	}
 
-	return objects.NewError(
-		"Not supported operator: %s",
-		exp.Operator)
+	return objects.NewTypeError(
+		"Not supported operator for BOOL: %s",
+		operator)
 }
 
-func (e *Evaluator) evalStringExpression(exp *ast.InfixExpression, env *objects.Storage) objects.Object {
-	left := e.eval(exp.Left, env).(*objects.String)
-
-	evalRight := e.eval(exp.Right, env)
+func (e *Evaluator) evalStringExpression(operator string, left *objects.String, evalRight objects.Object) objects.Object {
+	// string repetition: "ab" * 3
+	if operator == "*" && evalRight.Type() == objects.INTEGER_OBJ {
+		return e.repeatString(left.Value, evalRight.(*objects.Integer).Value, "")
+	}
 
 	if evalRight.Type() != objects.STRING_OBJ {
-		return objects.NewError(
-			"Expected right value to be a String.\n\tGot: %v",
-			evalRight.Inspect())
+		return objects.NewTypeError(
+			"Expected right value of '%s' to be STRING.\n\tGot: %s",
+			operator, describeObject(evalRight))
 	}
 
 	right := evalRight.(*objects.String)
 
-	switch exp.Operator {
+	switch operator {
 	case "==":
-		return &objects.Boolean{Value: left.Value == right.Value}
+		return selectBoolObject(left.Value == right.Value)
 	case "!=":
-		return &objects.Boolean{Value: left.Value != right.Value}
+		return selectBoolObject(left.Value != right.Value)
 	case "+":
+		if err := e.checkStringSize(int64(len(left.Value)) + int64(len(right.Value))); err != nil {
+			return err
+		}
 		return &objects.String{Value: left.Value + right.Value}
 	}
 
-	return objects.NewError(
-		"Not supported operator: %s",
-		exp.Operator)
+	return objects.NewTypeError(
+		"Not supported operator for STRING: %s",
+		operator)
 }
 
-func (e *Evaluator) evalArithmeticOperations(exp *ast.InfixExpression, env *objects.Storage) objects.Object {
-	left := e.eval(exp.Left, env).(*objects.Integer)
-
-	evalRight := e.eval(exp.Right, env)
-
+func (e *Evaluator) evalArithmeticOperations(operator string, left *objects.Integer, evalRight objects.Object) objects.Object {
 	if evalRight.Type() != objects.INTEGER_OBJ {
-		return objects.NewError(
-			"Expected right value of '%s' to be an integer. \n\tGot: %v",
-			exp.Operator, evalRight.Inspect())
+		return objects.NewTypeError(
+			"Expected right value of '%s' to be INTEGER.\n\tGot: %s",
+			operator, describeObject(evalRight))
 	}
 
 	right := evalRight.(*objects.Integer)
 
-	switch exp.Operator {
+	switch operator {
 	case "+":
 		return &objects.Integer{Value: left.Value + right.Value}
 	case "-":
@@ -135,6 +164,14 @@ func (e *Evaluator) evalArithmeticOperations(exp *ast.InfixExpression, env *obje
 	case "*":
 		return &objects.Integer{Value: left.Value * right.Value}
 	case "/":
+		if right.Value == 0 {
+			return objects.NewDivisionByZeroError("division by zero: %d / 0", left.Value)
+		}
+
+		if e.strictDivision && left.Value%right.Value != 0 {
+			return objects.NewArithmeticError("inexact integer division; use // or floats")
+		}
+
 		return &objects.Integer{Value: left.Value / right.Value}
 	case ">":
 		return selectBoolObject(left.Value > right.Value)
@@ -146,78 +183,513 @@ func (e *Evaluator) evalArithmeticOperations(exp *ast.InfixExpression, env *obje
 		return selectBoolObject(left.Value != right.Value)
 	}
 
-	return objects.NewError(
-		"Not supported operator: %s",
-		exp.Operator,
+	return objects.NewTypeError(
+		"Not supported operator for INTEGER: %s",
+		operator,
+	)
+}
+
+// Floats (and integers compared with floats) only support comparisons, which follow IEEE
+// 754: NaN is not equal, lower nor greater than any value, including itself.
+func (e *Evaluator) evalFloatComparison(operator string, left, evalRight objects.Object) objects.Object {
+	if !isNumber(evalRight) {
+		return objects.NewTypeError(
+			"Expected right value of '%s' to be a number.\n\tGot: %s",
+			operator, describeObject(evalRight))
+	}
+
+	a, b := toFloat(left), toFloat(evalRight)
+
+	switch operator {
+	case ">":
+		return selectBoolObject(a > b)
+	case "<":
+		return selectBoolObject(a < b)
+	case "==":
+		return selectBoolObject(a == b)
+	case "!=":
+		return selectBoolObject(a != b)
+	}
+
+	return objects.NewTypeError(
+		"Not supported operator for %s: %s",
+		left.Type(), operator,
 	)
 }
 
 func (e *Evaluator) evalIfExpression(exp *ast.IfExpression, env *objects.Storage) objects.Object {
 	condition := e.eval(exp.Condition, env)
+	if isError(condition) {
+		return condition
+	}
 
 	if condition.Type() != objects.BOOL_OBJ {
-		return objects.NewError(
+		return objects.NewTypeError(
 			"Expected boolean expression for 'if' condition.\n\t%v",
 			condition.Inspect(),
 		)
 	}
 
+	var result objects.Object
 	if condition == true_obj {
-		return e.eval(exp.Consequence, env)
+		result = e.eval(exp.Consequence, env)
+	} else if exp.Alternative != nil {
+		result = e.eval(exp.Alternative, env)
 	}
 
-	if exp.Alternative != nil {
-		return e.eval(exp.Alternative, env)
+	// when no branch runs (or the branch is empty) the "si" evaluates to null
+	if result == nil {
+		return null_obj
 	}
 
-	return nil
+	return result
 }
 
 func (e *Evaluator) evalFunctionCall(fun *ast.FunctionCall, env *objects.Storage) objects.Object {
-	f, ok := e.eval(fun.Identifier, env).(*objects.FunctionObject)
-	if !ok {
-		return objects.NewError("Function '%s' not found", fun.Identifier.ToString(0))
+	f, args := e.evalCall(fun, env)
+	if isError(f) {
+		return f
 	}
 
-	// check argument list size
-	if len(fun.Arguments) != len(f.Parameters) {
-		return objects.NewError("Number of Arguments mismatch with number of Parameters")
+	return e.applyFunction(f, args, env)
+}
+
+// Returned by "retorna f(x)". The call is made by applyFunction after the current function
+// ends, so tail calls do not grow the stack.
+type tailCall struct {
+	fn   objects.Object
+	args []objects.Object
+}
+
+func (t *tailCall) Type() objects.ObjectType {
+	return "TAIL_CALL"
+}
+func (t *tailCall) Inspect() string {
+	return "tail call: " + t.fn.Inspect()
+}
+
+func (e *Evaluator) evalTailCall(fun *ast.FunctionCall, env *objects.Storage) objects.Object {
+	f, args := e.evalCall(fun, env)
+	if isError(f) {
+		return f
 	}
 
-	// eval every argument
-	args := e.evalExpressions(fun.Arguments, env)
-	if len(args) == 1 && isError(args[0]) {
-		return args[0]
+	return &objects.ReturnObject{Value: &tailCall{fn: f, args: args}}
+}
+
+// Evaluates the function and the arguments of a call, without calling it. If any of them
+// fails the error is returned on place of the function.
+func (e *Evaluator) evalCall(fun *ast.FunctionCall, env *objects.Storage) (objects.Object, []objects.Object) {
+	f := e.eval(fun.Identifier, env)
+	if isError(f) {
+		return f, nil
 	}
 
-	// Create a local scope (with maximum recurssion level)
-	localEnv, err := objects.NewEnclosedStorage(env)
-	if err != nil {
-		return objects.NewError("%s", err.Error())
+	if !isCallable(f) {
+		return objects.NewTypeError("%s is not callable", describeObject(f)), nil
 	}
 
-	for i, param := range f.Parameters {
-		localEnv.Set(param.Value, args[i])
+	// eval every argument
+	args := e.evalExpressions(fun.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0], nil
 	}
 
-	// unwrap the returned value
-	result := e.eval(f.Body, localEnv)
-	unwrapped, ok := result.(*objects.ReturnObject)
-	if ok {
-		return unwrapped.Value
-	} else {
-		return result
+	return f, args
+}
+
+// Calls a function object (user defined or builtin) with already evaluated arguments.
+//
+// Tail calls returned by the function are made here, on place of the finished call, so
+// tail recursion does not increase the recursion level.
+func (e *Evaluator) applyFunction(fn objects.Object, args []objects.Object, env *objects.Storage) objects.Object {
+	var finished *objects.Storage // scope of the call replaced by a tail call
+
+	for {
+		switch f := fn.(type) {
+		case *Builtin:
+			if finished != nil {
+				env = finished
+			}
+
+			return f.Fn(e, env, args...)
+
+		case *objects.FunctionObject:
+			// check argument list size
+			if len(args) != len(f.Parameters) {
+				return objects.NewArgumentError("Number of Arguments mismatch with number of Parameters")
+			}
+
+			var localEnv *objects.Storage
+			if finished != nil {
+				// the variables of the finished call remain visible to the tail call, as
+				// they would be without replacing it
+				localEnv = finished.Clone()
+			} else {
+				// Create a local scope (with maximum recurssion level)
+				var err error
+				localEnv, err = objects.NewEnclosedStorage(env)
+				if err != nil {
+					return objects.NewLimitError("%s", err.Error())
+				}
+			}
+
+			for i, param := range f.Parameters {
+				if res := localEnv.Set(param.Value, args[i]); isError(res) {
+					return res
+				}
+			}
+
+			// unwrap the returned value
+			result := e.eval(f.Body, localEnv)
+			switch unwrapped := result.(type) {
+			case *objects.ReturnObject:
+				if call, ok := unwrapped.Value.(*tailCall); ok {
+					fn, args, finished = call.fn, call.args, localEnv
+					continue
+				}
+
+				return unwrapped.Value
+			case *objects.BreakObject, *objects.ContinueObject:
+				// loops cannot be controlled from inside a function call
+				return loopControlError(unwrapped)
+			case nil:
+				// functions without a result (like an empty body) return null
+				return null_obj
+			default:
+				return result
+			}
+		}
+
+		return objects.NewTypeError("Cannot call a non function value: %s", fn.Inspect())
 	}
 }
 
+// Return and error objects are propagated without unwrapping, so the enclosing function
+// (and not the loop) is the one that consumes them.
 func (e *Evaluator) evalForLoop(exp *ast.ForLoop, env *objects.Storage) objects.Object {
 	var value objects.Object
 	for i := 0; i < int(exp.Iterations.Value); i++ {
-		value = e.evalBlockStatement(exp.Body, env)
+		var stop bool
+		value, stop = loopControl(e.evalBlockStatement(exp.Body, env), exp.Label)
+		if stop {
+			return value
+		}
 	}
 	return value
 }
 
+func (e *Evaluator) evalForInLoop(exp *ast.ForInLoop, env *objects.Storage) objects.Object {
+	iterable := e.eval(exp.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	// ordered collections (hashes) are iterated by their keys
+	var items []objects.Object
+	switch collection := iterable.(type) {
+	case objects.Ordered:
+		items = collection.Keys()
+	case objects.Iterable:
+		items = collection.Items()
+	default:
+		return objects.NewTypeError("Cannot iterate over %s", iterable.Type())
+	}
+
+	var value objects.Object
+	for _, item := range items {
+		if res := env.Set(exp.Variable.Value, item); isError(res) {
+			return res
+		}
+
+		var stop bool
+		value, stop = loopControl(e.evalBlockStatement(exp.Body, env), exp.Label)
+		if stop {
+			return value
+		}
+	}
+
+	return value
+}
+
+func (e *Evaluator) evalWhileLoop(exp *ast.WhileLoop, env *objects.Storage) objects.Object {
+	var value objects.Object
+	for {
+		condition := e.eval(exp.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+
+		if condition.Type() != objects.BOOL_OBJ {
+			return objects.NewTypeError(
+				"Expected boolean expression for 'while' condition.\n\t%v",
+				condition.Inspect(),
+			)
+		}
+
+		if condition == false_obj {
+			return value
+		}
+
+		var stop bool
+		value, stop = loopControl(e.evalBlockStatement(exp.Body, env), exp.Label)
+		if stop {
+			return value
+		}
+	}
+}
+
+// Decides what a loop does with the result of its body. When "stop" is true the loop ends
+// returning "result". Break and continue objects are consumed by the loop if they have no
+// label or the label of the loop, otherwise they are passed to the outer loops.
+func loopControl(value objects.Object, label *ast.Identifier) (result objects.Object, stop bool) {
+	switch value := value.(type) {
+	case *objects.BreakObject:
+		if value.Label == "" || value.Label == labelName(label) {
+			return null_obj, true
+		}
+		return value, true
+
+	case *objects.ContinueObject:
+		if value.Label == "" || value.Label == labelName(label) {
+			return null_obj, false
+		}
+		return value, true
+	}
+
+	return value, isReturn(value) || isError(value)
+}
+
+// Error for the break and continue objects that were not consumed by any loop
+func loopControlError(obj objects.Object) objects.Object {
+	var keyword, label string
+	switch obj := obj.(type) {
+	case *objects.BreakObject:
+		keyword, label = "romper", obj.Label
+	case *objects.ContinueObject:
+		keyword, label = "continuar", obj.Label
+	}
+
+	if label != "" {
+		return objects.NewNameError("Unknown loop label: %s", label)
+	}
+
+	return objects.NewSyntaxError("'%s' outside of a loop", keyword)
+}
+
+func labelName(label *ast.Identifier) string {
+	if label == nil {
+		return ""
+	}
+
+	return label.Value
+}
+
+func (e *Evaluator) evalHashLiteral(node *ast.HashLiteral, env *objects.Storage) objects.Object {
+	hash := objects.NewHash()
+
+	for _, pair := range node.Pairs {
+		key := e.eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(objects.Hashable)
+		if !ok {
+			return objects.NewTypeError("Unusable as hash key: %s", key.Type())
+		}
+
+		value := e.eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+	}
+
+	return hash
+}
+
+// Indexes arrays, strings and hashes. Missing hash keys evaluate to null, so every link
+// of a chain like 'data["users"][0]' is resolved one step at a time.
+func (e *Evaluator) evalIndexExpression(left objects.Object, index objects.Object) objects.Object {
+	switch left := left.(type) {
+	case *objects.Array:
+		idx, ok := index.(*objects.Integer)
+		if !ok {
+			return objects.NewTypeError("Array index must be an integer. \n\tGot: %s", index.Type())
+		}
+
+		if idx.Value < 0 || idx.Value >= int64(len(left.Elements)) {
+			return objects.NewIndexError("Index out of range: %d", idx.Value)
+		}
+
+		return left.Elements[idx.Value]
+
+	case *objects.String:
+		idx, ok := index.(*objects.Integer)
+		if !ok {
+			return objects.NewTypeError("String index must be an integer. \n\tGot: %s", index.Type())
+		}
+
+		runes := []rune(left.Value)
+		if idx.Value < 0 || idx.Value >= int64(len(runes)) {
+			return objects.NewIndexError("Index out of range: %d", idx.Value)
+		}
+
+		return &objects.String{Value: string(runes[idx.Value])}
+
+	case *objects.Hash:
+		key, ok := index.(objects.Hashable)
+		if !ok {
+			return objects.NewTypeError("Unusable as hash key: %s", index.Type())
+		}
+
+		value, ok := left.Get(key)
+		if !ok {
+			return null_obj
+		}
+
+		return value
+	}
+
+	return objects.NewTypeError("Index operation not supported on %s", left.Type())
+}
+
+// Assigns the value to an already declared variable (on the scope where it was declared)
+// or to an index of an array or hash. The assigned value is returned, so assignments can
+// be chained.
+func (e *Evaluator) evalAssignExpression(node *ast.AssignExpression, env *objects.Storage) objects.Object {
+	value := e.eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		return env.Update(target.Value, value)
+
+	case *ast.IndexExpression:
+		left := e.eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		index := e.eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+
+		return e.evalIndexAssignment(left, index, value)
+	}
+
+	return objects.NewAssignmentError("Invalid assignment target: %s", node.Target.TokenLiteral())
+}
+
+func (e *Evaluator) evalIndexAssignment(left, index, value objects.Object) objects.Object {
+	switch left := left.(type) {
+	case *objects.Array:
+		idx, ok := index.(*objects.Integer)
+		if !ok {
+			return objects.NewTypeError("Array index must be an integer. \n\tGot: %s", index.Type())
+		}
+
+		if idx.Value < 0 || idx.Value >= int64(len(left.Elements)) {
+			return objects.NewIndexError("Index out of range: %d", idx.Value)
+		}
+
+		left.Elements[idx.Value] = value
+		return value
+
+	case *objects.Hash:
+		key, ok := index.(objects.Hashable)
+		if !ok {
+			return objects.NewTypeError("Unusable as hash key: %s", index.Type())
+		}
+
+		left.Set(key, value)
+		return value
+
+	case *objects.String:
+		return objects.NewTypeError("Cannot assign to index of %s: strings are immutable",
+			describeObject(left))
+	}
+
+	return objects.NewTypeError("Index assignment not supported on %s", left.Type())
+}
+
+// Resolves "left.member". On hashes this is the same as 'left["member"]', while on other
+// types the member is looked up on the methods of the type.
+func (e *Evaluator) evalMemberExpression(left objects.Object, member string) objects.Object {
+	if hash, ok := left.(*objects.Hash); ok {
+		value, ok := hash.Get(&objects.String{Value: member})
+		if !ok {
+			return null_obj
+		}
+
+		return value
+	}
+
+	if method, ok := methods[left.Type()][member]; ok {
+		return bindMethod(member, method, left)
+	}
+
+	return objects.NewTypeError("Cannot access member '%s' of %s", member, left.Type())
+}
+
+// Joins "times" copies of the string with the given separator
+func (e *Evaluator) repeatString(str string, times int64, sep string) objects.Object {
+	if times < 0 {
+		return objects.NewArgumentError("Cannot repeat a string a negative number of times: %d", times)
+	}
+
+	if times == 0 {
+		return &objects.String{Value: ""}
+	}
+
+	unit := int64(len(str) + len(sep))
+	if unit > 0 && times > math.MaxInt/unit {
+		return objects.NewLimitError("result too large")
+	}
+
+	if err := e.checkStringSize(unit*times - int64(len(sep))); err != nil {
+		return err
+	}
+
+	return &objects.String{Value: strings.Repeat(str+sep, int(times-1)) + str}
+}
+
+// Returns an error if a string of the given size exceeds the configured limit
+func (e *Evaluator) checkStringSize(size int64) objects.Object {
+	if e.maxStringSize > 0 && size > e.maxStringSize {
+		return objects.NewLimitError(
+			"result too large: string of %d bytes exceeds the limit of %d", size, e.maxStringSize)
+	}
+
+	return nil
+}
+
+// Returns an error if an array of the given length exceeds the configured limit
+func (e *Evaluator) checkArraySize(size int64) objects.Object {
+	if e.maxArraySize > 0 && size > e.maxArraySize {
+		return objects.NewLimitError(
+			"result too large: array of %d elements exceeds the limit of %d", size, e.maxArraySize)
+	}
+
+	return nil
+}
+
+// Describes a value with its type for error messages, like: STRING ("1")
+func describeObject(obj objects.Object) string {
+	if str, ok := obj.(*objects.String); ok {
+		return fmt.Sprintf("%s (%q)", obj.Type(), str.Value)
+	}
+
+	return fmt.Sprintf("%s (%s)", obj.Type(), obj.Inspect())
+}
+
+// Every boolean produced by the evaluator comes from here, so booleans can be compared by
+// reference against true_obj and false_obj.
 func selectBoolObject(exp bool) *objects.Boolean {
 	if exp {
 		return true_obj
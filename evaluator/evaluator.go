@@ -1,6 +1,12 @@
 package evaluator
 
 import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/sl2.0/ast"
 	"github.com/sl2.0/objects"
 	"github.com/sl2.0/parser"
@@ -10,11 +16,33 @@ import (
 var (
 	true_obj  = &objects.Boolean{Value: true}
 	false_obj = &objects.Boolean{Value: false}
+	null_obj  = &objects.Null{}
 )
 
 type Evaluator struct {
-	errors  []string
-	program *ast.Program
+	errors   []string
+	warnings []string
+	program  *ast.Program
+	env      *objects.Storage // environment of the last evaluation
+
+	integerFormat objects.IntegerFormat // format used by Inspect to display integers
+
+	// limits for the values produced by operations (0 means unlimited)
+	maxStringSize int64
+	maxArraySize  int64
+
+	strictDivision bool // integer divisions with remainder are errors
+
+	disableTailCalls bool // calls on "retorna" are made like any other call
+
+	output        io.Writer // where print writes (os.Stdout by default)
+	maxOutputSize int64     // limit of bytes written to the output (0 means unlimited)
+	outputSize    int64     // bytes already written to the output
+
+	clock func() time.Time // source of the current time for builtins like now
+
+	// provides the values of identifiers that are not declared (nor builtins)
+	resolver func(name string) (objects.Object, bool)
 }
 
 func NewFromInput(input string) *Evaluator {
@@ -49,6 +77,12 @@ func NewFromProgram(ast *ast.Program) *Evaluator {
 	return eval
 }
 
+// Returns an evaluator without a program, used to evaluate standalone inputs (like with
+// TypeOf) on the given environment.
+func NewWithEnvironment(env *objects.Storage) *Evaluator {
+	return &Evaluator{env: env}
+}
+
 func (e *Evaluator) Errors() []string {
 	return e.errors
 }
@@ -57,10 +91,129 @@ func (e *Evaluator) HasErrors() bool {
 	return len(e.errors) != 0
 }
 
+// Warnings are problems found on evaluation that do not stop the program, like builtin
+// functions shadowed by user declarations.
+func (e *Evaluator) Warnings() []string {
+	return e.warnings
+}
+
+// Sets the format used to display integer results with Inspect. Defaults to plain.
+func (e *Evaluator) SetIntegerFormat(format objects.IntegerFormat) {
+	e.integerFormat = format
+}
+
+// Sets the maximum size (in bytes) of the strings produced by operations like
+// concatenation and repetition. Zero means unlimited.
+func (e *Evaluator) SetMaxStringSize(size int64) {
+	e.maxStringSize = size
+}
+
+// Sets the maximum number of elements of the arrays produced by builtins like range.
+// Zero means unlimited.
+func (e *Evaluator) SetMaxArraySize(size int64) {
+	e.maxArraySize = size
+}
+
+// On strict division mode, integer divisions that are not exact (like 7 / 2) are errors
+// instead of being truncated. Disabled by default.
+func (e *Evaluator) SetStrictDivision(strict bool) {
+	e.strictDivision = strict
+}
+
+// Sets the writer used by builtins like print. Defaults to the standard output.
+func (e *Evaluator) SetOutput(output io.Writer) {
+	e.output = output
+}
+
+// Sets the maximum number of bytes the program can write to the output. Once exceeded,
+// writes fail and the program stops with an error. Zero means unlimited.
+func (e *Evaluator) SetMaxOutputSize(size int64) {
+	e.maxOutputSize = size
+}
+
+// Calls made by "retorna" (tail calls) replace the call of the function that returns, so
+// tail recursive functions are not limited by the recursion level. Enabled by default.
+func (e *Evaluator) SetTailCalls(enabled bool) {
+	e.disableTailCalls = !enabled
+}
+
+// Sets the function used to get the current time (time.Now by default), so the time seen
+// by the programs can be controlled.
+func (e *Evaluator) SetClock(clock func() time.Time) {
+	e.clock = clock
+}
+
+// Sets a function consulted for identifiers that are neither declared nor builtins, so
+// hosts can lazily provide values. If it returns false the identifier cannot be resolved.
+func (e *Evaluator) SetResolver(resolver func(name string) (objects.Object, bool)) {
+	e.resolver = resolver
+}
+
+var errOutputLimit = errors.New("output limit exceeded")
+
+// Writes the text to the output, failing if the output limit would be exceeded. Nothing is
+// written in that case.
+func (e *Evaluator) write(text string) error {
+	size := int64(len(text))
+	if e.maxOutputSize > 0 && e.outputSize+size > e.maxOutputSize {
+		return errOutputLimit
+	}
+
+	output := e.output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	e.outputSize += size
+	_, err := io.WriteString(output, text)
+
+	return err
+}
+
+func (e *Evaluator) now() time.Time {
+	if e.clock == nil {
+		return time.Now()
+	}
+
+	return e.clock()
+}
+
+// Returns the representation of an evaluated object, using the evaluator display settings.
+func (e *Evaluator) Inspect(obj objects.Object) string {
+	return objects.InspectWithFormat(obj, e.integerFormat)
+}
+
 func (e *Evaluator) EvalProgram(env *objects.Storage) objects.Object {
+	e.env = env
 	return e.eval(e.program, env)
 }
 
+// Parses and evaluates the input, returning the type name of the resulting value instead
+// of the value itself. The input is evaluated on the environment of the last evaluation.
+func (e *Evaluator) TypeOf(input string) (string, error) {
+	pars := parser.NewParser(input)
+	program := pars.ParseProgram()
+
+	if pars.HasErrors() {
+		return "", errors.New(strings.Join(pars.Errors(), "\n"))
+	}
+
+	if e.env == nil {
+		e.env = objects.NewStorage()
+	}
+
+	res := e.eval(program, e.env)
+	if res == nil {
+		return "", errors.New("No returned values")
+	}
+
+	if isError(res) {
+		return "", errors.New(res.Inspect())
+	}
+
+	return string(res.Type()), nil
+}
+
 /*
 eval evaluates every statement or expression within the program recursivelly
 
@@ -83,14 +236,28 @@ func (e *Evaluator) eval(node ast.Node, env *objects.Storage) objects.Object {
 			return val
 		}
 
+		e.warnIfShadowsBuiltin(node.Identifier.Value, env)
+
 		return env.Set(node.Identifier.Value, val)
 
 	case *ast.Identifier:
 		val, ok := env.Get(node.Value)
-		if !ok {
-			return objects.NewError("Cannot resolve identifier: %s", node.Value)
+		if ok {
+			return val
+		}
+
+		// user defined identifiers take precedence over builtins
+		if builtin, ok := builtins[node.Value]; ok {
+			return builtin
 		}
-		return val
+
+		if e.resolver != nil {
+			if val, ok := e.resolver(node.Value); ok && val != nil {
+				return val
+			}
+		}
+
+		return objects.NewNameError("Cannot resolve identifier: %s", node.Value)
 
 	case *ast.FunctionStatement:
 		f := &objects.FunctionObject{
@@ -98,7 +265,11 @@ func (e *Evaluator) eval(node ast.Node, env *objects.Storage) objects.Object {
 			Body:       node.Body,
 		}
 
-		env.Set(node.Identifier.Value, f)
+		e.warnIfShadowsBuiltin(node.Identifier.Value, env)
+
+		if res := env.SetFunction(node.Identifier.Value, f); isError(res) {
+			return res
+		}
 
 		return f
 
@@ -118,10 +289,26 @@ func (e *Evaluator) eval(node ast.Node, env *objects.Storage) objects.Object {
 	case *ast.ForLoop:
 		return e.evalForLoop(node, env)
 
+	case *ast.WhileLoop:
+		return e.evalWhileLoop(node, env)
+
+	case *ast.ForInLoop:
+		return e.evalForInLoop(node, env)
+
 	case *ast.ReturnStatement:
+		if call, ok := node.ReturnValue.(*ast.FunctionCall); ok && !e.disableTailCalls {
+			return e.evalTailCall(call, env)
+		}
+
 		val := e.eval(node.ReturnValue, env)
 		return &objects.ReturnObject{Value: val}
 
+	case *ast.BreakStatement:
+		return &objects.BreakObject{Label: labelName(node.Label)}
+
+	case *ast.ContinueStatement:
+		return &objects.ContinueObject{Label: labelName(node.Label)}
+
 		// -- Expressions --
 	case *ast.PrefixExpression:
 		return e.evalPrefix(node, env)
@@ -143,9 +330,46 @@ func (e *Evaluator) eval(node ast.Node, env *objects.Storage) objects.Object {
 
 	case *ast.StringLiteral:
 		return &objects.String{Value: node.Value}
+
+	case *ast.NullLiteral:
+		return null_obj
+
+	case *ast.ArrayLiteral:
+		elements := e.evalExpressions(node.Elements, env)
+		if len(elements) == 1 && isError(elements[0]) {
+			return elements[0]
+		}
+		return &objects.Array{Elements: elements}
+
+	case *ast.HashLiteral:
+		return e.evalHashLiteral(node, env)
+
+	case *ast.IndexExpression:
+		left := e.eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		index := e.eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+
+		return e.evalIndexExpression(left, index)
+
+	case *ast.AssignExpression:
+		return e.evalAssignExpression(node, env)
+
+	case *ast.MemberExpression:
+		left := e.eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		return e.evalMemberExpression(left, node.Member.Value)
 	}
 
-	return objects.NewError("Cannot evaluate node: %s", node.ToString(0))
+	return objects.NewSyntaxError("Cannot evaluate node: %s", node.ToString(0))
 }
 
 func (e *Evaluator) evalBlockStatement(node *ast.BlockStatement, env *objects.Storage) objects.Object {
@@ -156,7 +380,8 @@ func (e *Evaluator) evalBlockStatement(node *ast.BlockStatement, env *objects.St
 
 		if res != nil {
 			rt := res.Type()
-			if rt == objects.RETURN_OBJ || rt == objects.ERROR_OBJ {
+			if rt == objects.RETURN_OBJ || rt == objects.ERROR_OBJ ||
+				rt == objects.BREAK_OBJ || rt == objects.CONTINUE_OBJ {
 				return res
 			}
 		}
@@ -165,22 +390,44 @@ func (e *Evaluator) evalBlockStatement(node *ast.BlockStatement, env *objects.St
 	return res
 }
 
+// The result of a program is the value of its last expression or variable declaration, so
+// other statements (like function declarations) do not hide it. If there is none, the
+// result of the last statement is returned.
 func (e *Evaluator) evalStatements(stmts []ast.Statement, env *objects.Storage) objects.Object {
-	var res objects.Object
+	var res, last objects.Object
 
 	for _, value := range stmts {
 		res = e.eval(value, env)
 
 		switch res := res.(type) {
 		case *objects.ReturnObject:
+			// outside of functions there is no call to replace
+			if call, ok := res.Value.(*tailCall); ok {
+				return e.applyFunction(call.fn, call.args, env)
+			}
+
 			return res.Value
 
 		case *objects.ErrorObject:
 			return res
+
+		case *objects.BreakObject, *objects.ContinueObject:
+			return loopControlError(res)
+		}
+
+		switch value.(type) {
+		case *ast.ExpressionStatement, *ast.VarStatement:
+			if res != nil {
+				last = res
+			}
 		}
 	}
 
-	return res
+	if last == nil {
+		return res
+	}
+
+	return last
 }
 
 func (e *Evaluator) evalExpressions(exps []ast.Expression, env *objects.Storage) []objects.Object {
@@ -198,10 +445,28 @@ func (e *Evaluator) evalExpressions(exps []ast.Expression, env *objects.Storage)
 	return res
 }
 
+// Declaring an identifier with the name of a builtin is allowed, but is reported the first
+// time it happens because the builtin is no longer accessible.
+func (e *Evaluator) warnIfShadowsBuiltin(ident string, env *objects.Storage) {
+	if _, ok := builtins[ident]; !ok {
+		return
+	}
+
+	if _, declared := env.Get(ident); declared {
+		return
+	}
+
+	e.warnings = append(e.warnings, "Declaration of '"+ident+"' shadows a builtin function")
+}
+
 func isError(obj objects.Object) bool {
-	if obj != nil {
-		rt := obj.Type()
-		return rt == objects.ERROR_OBJ
+	return objects.IsError(obj)
+}
+
+func isCallable(obj objects.Object) bool {
+	switch obj.(type) {
+	case *objects.FunctionObject, *Builtin:
+		return true
 	}
 
 	return false
@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"math"
+	"slices"
 	"strings"
 	"testing"
 
@@ -141,6 +143,14 @@ func TestIfEvaluation(t *testing.T) {
                 false
             }
             `, expected: false},
+		{tcase: "si(false){1}sino si(true){2}sino{3}", expected: 2},
+		{tcase: "si(false){1}sino si(false){2}sino{3}", expected: 3},
+		{tcase: "var x = 3; si(x == 1){1}elif(x == 2){2}elif(x == 3){30}sino{4}", expected: 30},
+		{tcase: `func signo(n) {
+				si (n < 0) { retorna -1; } elif (n == 0) { retorna 0; }
+				retorna 1;
+			}
+			signo(-5) * 100 + signo(0) * 10 + signo(8)`, expected: -99},
 	}
 
 	for _, tc := range testCases {
@@ -186,12 +196,16 @@ func TestEvalError(t *testing.T) {
 		tcase    string
 		expected string
 	}{
-		{tcase: "2*true;", expected: "Expected right value of '*' to be an integer."},
-		{tcase: "true*2;", expected: "Expected right value to be a boolean."},
-		{tcase: "si(true*2){2}", expected: "Expected boolean expression for 'if' condition.\n" +
-			"\tExpected right value to be a boolean." +
-			"\n\tGot: 2",
-		},
+		{tcase: "2*true;", expected: "Expected right value of '*' to be INTEGER.\n\tGot: BOOL (true)"},
+		{tcase: "true*2;", expected: "Expected right value of '*' to be BOOL.\n\tGot: INTEGER (2)"},
+		{tcase: `1 + "1";`, expected: "Expected right value of '+' to be INTEGER.\n\tGot: STRING (\"1\")"},
+		{tcase: "true + 1;", expected: "Expected right value of '+' to be BOOL.\n\tGot: INTEGER (1)"},
+		{tcase: `"a" - 1;`, expected: "Expected right value of '-' to be STRING.\n\tGot: INTEGER (1)"},
+		{tcase: "true + false;", expected: "Not supported operator for BOOL: +"},
+		{tcase: `!"a";`, expected: "Expected BOOL expression for '!' operator.\n\tGot: STRING (\"a\")"},
+		{tcase: "si(2){2}", expected: "Expected boolean expression for 'if' condition.\n\t2"},
+		// the errors of the condition are returned unchanged
+		{tcase: "si(true*2){2}", expected: "Expected right value of '*' to be BOOL.\n\tGot: INTEGER (2)"},
 	}
 
 	for _, tc := range testCases {
@@ -249,7 +263,7 @@ func TestFunctionCalls(t *testing.T) {
 
             higher(func() {
                 retorna 2;
-            };, 8);`,
+            }, 8);`,
 			expected: 8,
 		},
 		{
@@ -316,3 +330,1032 @@ func TestMaxRecursion(t *testing.T) {
 		t.Errorf("Expected msg '%s'. Got %s", expected, evaluated.Inspect())
 	}
 }
+
+func TestWhileLoop(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected int64
+	}{
+		{tcase: `var nuevo = 0;
+				 mientras (nuevo < 10) {
+					 var nuevo = nuevo + 1;
+				 }
+				 nuevo`,
+			expected: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			continue
+		}
+
+		testInteger(t, evaluated, tc.expected)
+	}
+}
+
+func TestReturnInsideLoops(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected int64
+	}{
+		{tcase: `func firstOver(limit) {
+					var i = 0;
+					mientras (true) {
+						var i = i + 1;
+						si (i > limit) {
+							retorna i;
+						}
+					}
+					retorna 0;
+				 }
+				 firstOver(5)`,
+			expected: 6,
+		},
+		{tcase: `func countTo(limit) {
+					var i = 0;
+					repetir 100 {
+						si (i == limit) {
+							retorna i * 2;
+						}
+						var i = i + 1;
+					}
+					retorna 0;
+				 }
+				 countTo(3)`,
+			expected: 6,
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			continue
+		}
+
+		testInteger(t, evaluated, tc.expected)
+	}
+}
+
+func TestBreakAndContinue(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `var pairs = [];
+			externo: repetir i en [1, 2, 3] {
+				repetir j en [1, 2, 3] {
+					si (j == 2) {
+						continuar
+					}
+					si (i == 2) {
+						romper externo;
+					}
+					splice(pairs, 0, 0, [i, j]);
+				}
+			}
+			pairs`,
+			expected: `[[1, 3], [1, 1]]`,
+		},
+		{
+			tcase: `var seen = [];
+			externo: repetir 3 {
+				var i = 0;
+				mientras (true) {
+					var i = i + 1;
+					si (i == 2) {
+						continuar externo;
+					}
+					splice(seen, 0, 0, i);
+				}
+			}
+			seen`,
+			expected: `[1, 1, 1]`,
+		},
+		{
+			tcase: `var seen = [];
+			repetir x en [1, 2, 3, 4] {
+				si (x == 3) {
+					romper
+				}
+				splice(seen, 0, 0, x);
+			}
+			seen`,
+			expected: `[2, 1]`,
+		},
+		{
+			tcase: `repetir x en [1] {
+				romper otro;
+			}`,
+			expected: errorMessage("Unknown loop label: otro"),
+		},
+		{
+			tcase: `func salir() { romper; }
+			repetir x en [1] {
+				salir();
+			}`,
+			expected: errorMessage("'romper' outside of a loop"),
+		},
+		{
+			tcase:    `continuar`,
+			expected: errorMessage("'continuar' outside of a loop"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestIndexChains(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `var data = {
+						"users": [{"name": "ana"}, {"name": "juan"}],
+					};
+					data["users"][1]["name"]`,
+			expected: "juan",
+		},
+		{
+			tcase:    `var obj = {"a": {"b": {"c": 3}}}; obj.a.b.c`,
+			expected: 3,
+		},
+		{
+			tcase:    `var matrix = [[1, 2], [3, [4, 5]]]; matrix[1][1][0]`,
+			expected: 4,
+		},
+		{
+			tcase:    `var obj = {"a": {"b": 1}}; obj.a["b"] + obj["a"].b`,
+			expected: 2,
+		},
+		{
+			tcase:    `[-1, -2][1]`,
+			expected: -2,
+		},
+		{
+			tcase:    `{-1: "a", 1: "b"}[-1]`,
+			expected: "a",
+		},
+		{
+			tcase:    `[1, 2][2]`,
+			expected: "Index out of range: 2",
+		},
+		{
+			tcase:    `var obj = {"a": 1}; obj.b.c`,
+			expected: "Cannot access member 'c' of NULL",
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		switch expected := tc.expected.(type) {
+		case int:
+			testInteger(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Type() == objects.ERROR_OBJ {
+				testError(t, evaluated, expected)
+			} else {
+				testString(t, evaluated, expected)
+			}
+		}
+	}
+
+	evaluated := parseAndEval(t, `var obj = {"a": {"b": 1}}; obj.a.missing`)
+	if evaluated != nil && evaluated.Type() != objects.NULL_OBJ {
+		t.Errorf("Expected NULL for a missing link. Got %s", evaluated.Inspect())
+	}
+}
+
+func TestNullEquality(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected bool
+	}{
+		{tcase: "null == null", expected: true},
+		{tcase: "null != null", expected: false},
+		{tcase: "null == 1", expected: false},
+		{tcase: "1 == null", expected: false},
+		{tcase: "1 != null", expected: true},
+		{tcase: `"null" == null`, expected: false},
+		{tcase: "false == null", expected: false},
+		{tcase: `[1] != null`, expected: true},
+		{tcase: `var h = {"a": 1}; h.b == null`, expected: true},
+		{tcase: `var h = {"a": 1}; h.a != null`, expected: true},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			continue
+		}
+
+		testBool(t, evaluated, tc.expected)
+	}
+}
+
+func TestIfWithoutBranchIsNull(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: "si (false) { 1 }", expected: nil},
+		{tcase: "si (true) {}", expected: nil},
+		{tcase: "(si (false) { 1 }) == null", expected: true},
+		{tcase: `func f(n) {
+				si (n > 0) { retorna f(n - 1) }
+			}
+			f(3) == null`, expected: true},
+		{tcase: "var x = si (false) { 1 }; x != 1", expected: true},
+		{tcase: "var x = si (false) { 1 }; x + 1", expected: errorMessage("Not supported infix operation for NULL: +")},
+		{tcase: "var x = si (false) { 1 }; -x", expected: errorMessage("Expected INTEGER expression for '-' operator.")},
+		{tcase: "var x = si (false) { 1 }; !x", expected: errorMessage("Expected BOOL expression for '!' operator.")},
+		{tcase: "[si (false) { 1 }][0] == null", expected: true},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			t.Errorf("Expected a value for %q. Got <nil>", tc.tcase)
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+
+	evaluated := parseAndEval(t, "[si (false) { 1 }, 2]")
+	if evaluated != nil {
+		testInspect(t, evaluated, objects.ARRAY_OBJ, "[null, 2]")
+	}
+}
+
+func TestBooleanSingletons(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected *objects.Boolean
+	}{
+		{tcase: "true", expected: true_obj},
+		{tcase: "false", expected: false_obj},
+		{tcase: "!true", expected: false_obj},
+		{tcase: "1 < 2", expected: true_obj},
+		{tcase: "1 > 2", expected: false_obj},
+		{tcase: "1 == 1", expected: true_obj},
+		{tcase: "1 != 1", expected: false_obj},
+		{tcase: "true == true", expected: true_obj},
+		{tcase: "true != true", expected: false_obj},
+		{tcase: `"a" == "a"`, expected: true_obj},
+		{tcase: `"a" != "a"`, expected: false_obj},
+		{tcase: "null == null", expected: true_obj},
+		{tcase: "null != 1", expected: true_obj},
+		{tcase: "[true][0]", expected: true_obj},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		if evaluated != tc.expected {
+			t.Errorf("Expected the shared %s object for '%s'. Got a new instance",
+				tc.expected.Inspect(), tc.tcase)
+		}
+	}
+}
+
+func TestFloatComparison(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `medio < uno`, expected: true_obj},
+		{tcase: `medio > uno`, expected: false_obj},
+		{tcase: `medio == from_json("0.5")`, expected: true_obj},
+		{tcase: `medio != medio`, expected: false_obj},
+		{tcase: `1 > medio`, expected: true_obj},
+		{tcase: `medio < 0`, expected: false_obj},
+		{tcase: `from_json("1.0") == 1`, expected: true_obj},
+		{tcase: `inf > 9223372036854775807`, expected: true_obj},
+		{tcase: `nan == nan`, expected: false_obj},
+		{tcase: `nan != nan`, expected: true_obj},
+		{tcase: `nan < uno`, expected: false_obj},
+		{tcase: `nan > uno`, expected: false_obj},
+		{tcase: `uno < nan`, expected: false_obj},
+		{tcase: `1 == nan`, expected: false_obj},
+		{tcase: `nan < inf`, expected: false_obj},
+		{tcase: `medio + medio`, expected: errorMessage("Not supported operator for FLOAT: +")},
+		{tcase: `medio < "1"`, expected: errorMessage("Expected right value of '<' to be a number.")},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		env := objects.NewStorage()
+		env.Set("medio", &objects.Float{Value: 0.5})
+		env.Set("uno", &objects.Float{Value: 1})
+		env.Set("inf", &objects.Float{Value: math.Inf(1)})
+		env.Set("nan", &objects.Float{Value: math.NaN()})
+
+		evaluated := ev.EvalProgram(env)
+
+		switch expected := tc.expected.(type) {
+		case *objects.Boolean:
+			// comparisons return the shared booleans
+			if evaluated != expected {
+				t.Errorf("Expected the shared %s object for '%s'. Got %s",
+					expected.Inspect(), tc.tcase, evaluated.Inspect())
+			}
+		default:
+			testObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestForInLoop(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `var total = 0;
+				 repetir n en [1, 2, 3, 4] {
+					 var total = total + n;
+				 }
+				 total`,
+			expected: 10,
+		},
+		{tcase: `var reversed = "";
+				 repetir ch en "hola" {
+					 var reversed = ch + reversed;
+				 }
+				 reversed`,
+			expected: "aloh",
+		},
+		{tcase: `repetir n en 5 { n }`,
+			expected: errorMessage("Cannot iterate over INTEGER"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestForInHashKeys(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `var prices = {"pan": 3, "leche": 5, "queso": 12};
+				 var total = 0;
+				 repetir k en prices {
+					 var total = total + prices[k];
+				 }
+				 total`,
+			expected: 20,
+		},
+		{tcase: `var h = {"b": 1, "a": 2, "c": 3};
+				 var order = "";
+				 repetir k en h {
+					 var order = order + k;
+				 }
+				 order`,
+			expected: "bac",
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestHashKeyOrder(t *testing.T) {
+	testCases := []struct {
+		setup    string
+		expected string
+	}{
+		{setup: `var h = {"c": 1, "a": 2, "b": 3};`, expected: `["c", "a", "b"]`},
+		{
+			// overriding a key keeps its position, new keys go at the end
+			setup: `var h = {"z": 1, "y": 2};
+			h["z"] = 3;
+			h["a"] = 4;`,
+			expected: `["z", "y", "a"]`,
+		},
+		{setup: `var h = merge({"b": 1, "a": 1}, {"c": 2, "b": 2});`, expected: `["b", "a", "c"]`},
+		{setup: `var h = from_json("{\"q\": 1, \"p\": 2}");`, expected: `["q", "p"]`},
+		{setup: `var h = {};`, expected: `[]`},
+	}
+
+	for _, tc := range testCases {
+		testKeyOrder(t, tc.setup, tc.expected)
+	}
+}
+
+// Ordered collection that traverses the keys of a hash in reverse order
+type reversedHash struct {
+	*objects.Hash
+}
+
+func (r reversedHash) Pairs() []objects.HashPair {
+	pairs := r.Hash.Pairs()
+	slices.Reverse(pairs)
+	return pairs
+}
+
+func (r reversedHash) Keys() []objects.Object {
+	keys := r.Hash.Keys()
+	slices.Reverse(keys)
+	return keys
+}
+
+func TestOrderedCollections(t *testing.T) {
+	hash := objects.NewHash()
+	hash.Set(&objects.String{Value: "a"}, &objects.Integer{Value: 1})
+	hash.Set(&objects.String{Value: "b"}, &objects.Integer{Value: 2})
+	hash.Set(&objects.String{Value: "c"}, &objects.Integer{Value: 3})
+
+	// every operation uses the order of the Ordered interface, not the one of the hash
+	for _, op := range keyOrderOperations {
+		ev := NewFromInput(op.program)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", op.program)
+		}
+
+		env := objects.NewStorage()
+		env.Set("h", reversedHash{Hash: hash})
+
+		evaluated := ev.EvalProgram(env)
+		if evaluated.Inspect() != `["c", "b", "a"]` {
+			t.Errorf("Wrong key order using %s. Expected [\"c\", \"b\", \"a\"]. Got %s",
+				op.name, evaluated.Inspect())
+		}
+	}
+}
+
+func TestFrozenEnvironment(t *testing.T) {
+	env := objects.NewStorage()
+	env.Set("limite", &objects.Integer{Value: 10})
+	env.Freeze()
+
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: "limite * 2", expected: 20},
+		{tcase: "var limite = 2;", expected: errorMessage("cannot modify frozen scope")},
+		{tcase: "func limite() { retorna 1; }", expected: errorMessage("cannot modify frozen scope")},
+		{tcase: "func doble(x) { retorna x * 2; }", expected: errorMessage("cannot modify frozen scope")},
+		{tcase: "limite = 2", expected: errorMessage("cannot modify frozen scope")},
+		{tcase: "noExiste = 2", expected: errorMessage("cannot modify frozen scope")},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		testObject(t, ev.EvalProgram(env), tc.expected)
+	}
+}
+
+func TestIntegerFormat(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		format   objects.IntegerFormat
+		expected string
+	}{
+		{tcase: "1000 * 1000", format: objects.PlainFormat, expected: "1000000"},
+		{tcase: "1000 * 1000", format: objects.GroupedFormat, expected: "1,000,000"},
+		{tcase: "-1234567", format: objects.GroupedFormat, expected: "-1,234,567"},
+		{tcase: "123456", format: objects.GroupedFormat, expected: "123,456"},
+		{tcase: "999", format: objects.GroupedFormat, expected: "999"},
+		{tcase: "1000 * 1000", format: objects.ScientificFormat, expected: "1e+06"},
+		{tcase: `"1000000"`, format: objects.GroupedFormat, expected: "1000000"},
+		{tcase: "-1500", format: objects.ScientificFormat, expected: "-1.5e+03"},
+		{tcase: "0", format: objects.ScientificFormat, expected: "0e+00"},
+		// above 2^53, where float64 loses precision
+		{tcase: "9007199254740993", format: objects.ScientificFormat, expected: "9.007199254740993e+15"},
+		// integers inside collections use the format too
+		{tcase: "[1000000, [2000]]", format: objects.GroupedFormat, expected: "[1,000,000, [2,000]]"},
+		{tcase: `{"a": 1000000, 1000: "b"}`, format: objects.GroupedFormat, expected: `{"a": 1,000,000, 1,000: "b"}`},
+		{tcase: "[1000000]", format: objects.ScientificFormat, expected: "[1e+06]"},
+		{tcase: "[1000000]", format: objects.PlainFormat, expected: "[1000000]"},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		ev.SetIntegerFormat(tc.format)
+		actual := ev.Inspect(ev.EvalProgram(objects.NewStorage()))
+
+		if actual != tc.expected {
+			t.Errorf("Expected '%s'. Got '%s'", tc.expected, actual)
+		}
+	}
+}
+
+func TestFunctionReassignment(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+		warnings int
+	}{
+		{
+			tcase:    `var partial = 5; partial`,
+			expected: 5,
+			warnings: 1,
+		},
+		{
+			tcase:    `var partial = 5; var partial = 6; partial`,
+			expected: 6,
+			warnings: 1,
+		},
+		{
+			tcase: `func bar() { retorna 1; }
+			var bar = 2;`,
+			expected: errorMessage("cannot reassign function: bar"),
+		},
+		{
+			tcase: `func bar() { retorna 1; }
+			func bar() { retorna 2; }
+			bar()`,
+			expected: 2,
+		},
+		{
+			tcase: `func bar() { retorna 1; }
+			func shadow() {
+				var bar = 2;
+				retorna bar;
+			}
+			shadow()`,
+			expected: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		testObject(t, ev.EvalProgram(objects.NewStorage()), tc.expected)
+
+		if len(ev.Warnings()) != tc.warnings {
+			t.Errorf("Expected %d warnings. Got %v", tc.warnings, ev.Warnings())
+		}
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		err      bool
+	}{
+		{input: "1 + 2", expected: "INTEGER"},
+		{input: `"a" + "b"`, expected: "STRING"},
+		{input: "1 < 2", expected: "BOOL"},
+		{input: "[1, 2]", expected: "ARRAY"},
+		{input: "func uno() { retorna 1; }", expected: "FUNCTION"},
+		{input: "partial", expected: "BUILTIN"},
+		{input: "1 +", err: true},
+		{input: "missing", err: true},
+	}
+
+	ev := NewWithEnvironment(objects.NewStorage())
+
+	for _, tc := range testCases {
+		actual, err := ev.TypeOf(tc.input)
+
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected an error for '%s'. Got %s", tc.input, actual)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %s", tc.input, err.Error())
+			continue
+		}
+
+		if actual != tc.expected {
+			t.Errorf("Expected '%s'. Got '%s'", tc.expected, actual)
+		}
+	}
+}
+
+func TestStringRepetition(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `"ab" * 3`, expected: "ababab"},
+		{tcase: `"ab" * 0`, expected: ""},
+		{tcase: `"ab" * -1`, expected: errorMessage("Cannot repeat a string a negative number of times: -1")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestMaxSizes(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `"x" * 10`, expected: "xxxxxxxxxx"},
+		{tcase: `"x" * 1000000000`, expected: errorMessage("result too large")},
+		{tcase: `"xxxxxx" + "xxxxx"`, expected: errorMessage("result too large")},
+		{tcase: `range(1000000000)`, expected: errorMessage("result too large")},
+		{tcase: `range(5, 10)[4]`, expected: 9},
+		{tcase: `var arr = range(5); splice(arr, 0, 0, 1, 2, 3, 4, 5, 6)`, expected: errorMessage("result too large")},
+		{tcase: `make_array(1000000000)`, expected: errorMessage("result too large")},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		ev.SetMaxStringSize(10)
+		ev.SetMaxArraySize(10)
+
+		testObject(t, ev.EvalProgram(objects.NewStorage()), tc.expected)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `puerto + 1`, expected: 8081},
+		{tcase: `var puerto = 1; puerto`, expected: 1},
+		{tcase: `nombre`, expected: "servidor"},
+		{tcase: `otro`, expected: errorMessage("Cannot resolve identifier: otro")},
+	}
+
+	config := map[string]objects.Object{
+		"puerto": &objects.Integer{Value: 8080},
+		"nombre": &objects.String{Value: "servidor"},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		ev.SetResolver(func(name string) (objects.Object, bool) {
+			value, ok := config[name]
+			return value, ok
+		})
+
+		testObject(t, ev.EvalProgram(objects.NewStorage()), tc.expected)
+	}
+}
+
+func TestAssignment(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `var a = 0; var b = 0; a = b = 5; [a, b]`, expected: `[5, 5]`},
+		{tcase: `var a = 0; var b = 1; var c = 2; a = b = c = 0; [a, b, c]`, expected: `[0, 0, 0]`},
+		{tcase: `var a = 1; (a = 4) + 1`, expected: 5},
+		{tcase: `var a = 1; var b = 2; a = b + 1 == 3; a`, expected: true},
+		{
+			tcase: `var arr = [1, 2]; var h = {"k": 0}; var n = 0;
+			n = arr[1] = h["k"] = h["nuevo"] = 7;
+			[n, arr, h]`,
+			expected: `[7, [1, 7], {"k": 7, "nuevo": 7}]`,
+		},
+		{
+			// assignments update the scope where the variable was declared
+			tcase: `var total = 0;
+			func sumar(x) { total = total + x; }
+			sumar(2);
+			sumar(3);
+			[total]`,
+			expected: `[5]`,
+		},
+		{tcase: `a = 1`, expected: errorMessage("Cannot resolve identifier: a")},
+		{tcase: `var arr = [1]; arr[3] = 1`, expected: errorMessage("Index out of range: 3")},
+		{tcase: `func f() {}
+		f = 1`, expected: errorMessage("cannot reassign function: f")},
+		{
+			tcase:    `var s = "hola"; s[0] = "x"`,
+			expected: errorMessage(`Cannot assign to index of STRING ("hola"): strings are immutable`),
+		},
+		{tcase: `var s = "hola"; s[0] = "x"; s`, expected: errorMessage("Cannot assign to index")},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testCollection(t, evaluated, tc.expected)
+	}
+}
+
+func TestStrictDivision(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		strict   bool
+		expected interface{}
+	}{
+		{tcase: `7 / 2`, strict: false, expected: 3},
+		{tcase: `-7 / 2`, strict: false, expected: -3},
+		{tcase: `8 / 2`, strict: true, expected: 4},
+		{tcase: `7 / 2`, strict: true, expected: errorMessage("inexact integer division; use // or floats")},
+		{tcase: `1 / 0`, strict: false, expected: errorMessage("division by zero")},
+		{tcase: `1 / 0`, strict: true, expected: errorMessage("division by zero")},
+	}
+
+	for _, tc := range testCases {
+		ev := NewFromInput(tc.tcase)
+		if ev == nil {
+			t.Fatalf("Parsing errors on: %s", tc.tcase)
+		}
+
+		ev.SetStrictDivision(tc.strict)
+
+		testObject(t, ev.EvalProgram(objects.NewStorage()), tc.expected)
+	}
+
+	ev := NewFromInput(`7 / 2`)
+	if ev == nil {
+		t.Fatalf("Parsing errors on: 7 / 2")
+	}
+
+	ev.SetStrictDivision(true)
+
+	err, ok := ev.EvalProgram(objects.NewStorage()).(*objects.ErrorObject)
+	if !ok || err.Kind() != objects.ArithmeticError {
+		t.Errorf("Expected an ArithmeticError for an inexact division")
+	}
+}
+
+func TestAnonymousFunctionExpressions(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{
+			tcase: `var ops = {"doble": func(x) { retorna x * 2; }, "neg": func(x) { retorna -x; }};
+			ops["doble"](21)`,
+			expected: 42,
+		},
+		{
+			tcase: `var ops = {
+				"suma": func(a, b) { retorna a + b; },
+			}
+			ops.suma(1, 2) + ops["suma"](3, 4)`,
+			expected: 10,
+		},
+		{
+			tcase: `var elegir = si (true) { func(x) { retorna x + 1; } } sino { func(x) { retorna x; } }
+			elegir(1)`,
+			expected: 2,
+		},
+		{tcase: `func(x) { retorna x * 3; }(5)`, expected: 15},
+		{tcase: `[func() { retorna 7; }][0]()`, expected: 7},
+		{
+			tcase: `var f = func(x) { retorna x; }
+			var g = func(x) { retorna f(x) + 1; }
+			g(1)`,
+			expected: 2,
+		},
+		{
+			tcase: `var total = 0;
+			4.times(func(i) { total = total + i; });
+			total`,
+			expected: 6,
+		},
+		{tcase: `func sumar(a, b) { retorna a + b; } sumar(1, 2)`, expected: 3},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+}
+
+func TestLastExpressionValue(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: `var x = 4; x`, expected: 4},
+		{tcase: `var x = 4; var y = 3`, expected: 3},
+		{tcase: `var y = 3;`, expected: 3},
+		{tcase: `5; func f() { retorna 1; }`, expected: 5},
+		{tcase: `var x = 7; repetir 0 { 1 }`, expected: 7},
+		{tcase: `"hola"; mientras (false) { 1 }`, expected: "hola"},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			t.Errorf("No value returned on '%s'", tc.tcase)
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+
+	// without expressions, the result of the last statement is returned
+	evaluated := parseAndEval(t, `func f() { retorna 1; }`)
+	if evaluated == nil || evaluated.Type() != objects.FUNC_OBJ {
+		t.Errorf("Expected a function to be returned. Got %v", evaluated)
+	}
+}
+
+func TestTailCalls(t *testing.T) {
+	sum := `func suma(n, acc) {
+		si (n == 0) { retorna acc; }
+		retorna suma(n - 1, acc + n);
+	}
+	suma(100000, 0)`
+
+	testCases := []struct {
+		tcase    string
+		expected interface{}
+	}{
+		{tcase: sum, expected: 5000050000},
+		{
+			tcase: `func par(n) { si (n == 0) { retorna true; } retorna impar(n - 1); }
+			func impar(n) { si (n == 0) { retorna false; } retorna par(n - 1); }
+			par(5001)`,
+			expected: false,
+		},
+		{
+			// the variables of the caller are still visible to the tail call
+			tcase: `func externo(x) {
+				func interno() { retorna x * 2; }
+				retorna interno();
+			}
+			externo(21)`,
+			expected: 42,
+		},
+		{
+			// the parameters of the tail call override the functions of the caller
+			tcase: `func b(helper) { retorna helper; }
+			func a() {
+				func helper() { retorna 0; }
+				retorna b(5);
+			}
+			a()`,
+			expected: 5,
+		},
+		{tcase: `func f(arr) { retorna first(arr); } f([4, 5])`, expected: 4},
+		{tcase: `func f(x) { retorna x; } retorna f(3);`, expected: 3},
+		{tcase: `func f() { retorna g(); } f()`, expected: errorMessage("Cannot resolve identifier: g")},
+		{
+			// only calls on "retorna" are tail calls
+			tcase: `func suma(n) { si (n == 0) { retorna 0; } retorna n + suma(n - 1); }
+			suma(1000)`,
+			expected: errorMessage("Max level of recursion reached"),
+		},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		testObject(t, evaluated, tc.expected)
+	}
+
+	// without tail calls the recursion level is reached
+	ev := NewFromInput(sum)
+	if ev == nil {
+		t.Fatalf("Parsing errors on: %s", sum)
+	}
+
+	ev.SetTailCalls(false)
+	testObject(t, ev.EvalProgram(objects.NewStorage()), errorMessage("Max level of recursion reached"))
+}
+
+func TestMaxOutputSize(t *testing.T) {
+	tcase := `var veces = 0;
+	repetir 100 {
+		print("linea");
+		veces = veces + 1;
+	}`
+
+	ev := NewFromInput(tcase)
+	if ev == nil {
+		t.Fatalf("Parsing errors on: %s", tcase)
+	}
+
+	var output strings.Builder
+	ev.SetOutput(&output)
+	ev.SetMaxOutputSize(20)
+
+	env := objects.NewStorage()
+	testObject(t, ev.EvalProgram(env), errorMessage("output limit exceeded"))
+
+	// the line that exceeds the limit is not written, and stops the program
+	if output.String() != strings.Repeat("linea\n", 3) {
+		t.Errorf("Unexpected output: %q", output.String())
+	}
+
+	veces, _ := env.Get("veces")
+	testInteger(t, veces, 3)
+}
+
+func TestErrorKinds(t *testing.T) {
+	testCases := []struct {
+		tcase    string
+		expected objects.ErrorKind
+	}{
+		{tcase: `10 / 0`, expected: objects.DivisionByZero},
+		{tcase: `noExiste + 1`, expected: objects.NameError},
+		{tcase: `noExiste = 1`, expected: objects.NameError},
+		{tcase: `1 + "a"`, expected: objects.TypeError},
+		{tcase: `first(1, 2)`, expected: objects.ArgumentError},
+		{tcase: `[1][5]`, expected: objects.IndexError},
+		{tcase: `func f() { retorna 1 + f(); } f()`, expected: objects.LimitError},
+		{tcase: `romper`, expected: objects.SyntaxError},
+		{tcase: `func f() {} f = 1`, expected: objects.AssignmentError},
+		// errors of the operands keep their kind
+		{tcase: `si (1 / 0 > 1) {}`, expected: objects.DivisionByZero},
+		{tcase: `!noExiste`, expected: objects.NameError},
+		{tcase: `-(1 / 0)`, expected: objects.DivisionByZero},
+		// calls keep the error of the called expression
+		{tcase: `5()`, expected: objects.TypeError},
+		{tcase: `var f = func() {}; f()()`, expected: objects.TypeError},
+		{tcase: `noExiste()`, expected: objects.NameError},
+		{tcase: `(1 / 0)()`, expected: objects.DivisionByZero},
+		{tcase: `var arr = [1]; arr[9]()`, expected: objects.IndexError},
+	}
+
+	for _, tc := range testCases {
+		evaluated := parseAndEval(t, tc.tcase)
+		if evaluated == nil {
+			continue
+		}
+
+		err, ok := evaluated.(*objects.ErrorObject)
+		if !ok {
+			t.Errorf("Expected an error on '%s'. Got %s", tc.tcase, evaluated.Inspect())
+			continue
+		}
+
+		if err.Kind() != tc.expected {
+			t.Errorf("Expected a %s on '%s'. Got %s: %s", tc.expected, tc.tcase, err.Kind(), err.Message())
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/sl2.0/objects"
+)
+
+// to_json(x) serializes arrays, hashes, strings, numbers, booleans and null to a JSON
+// string. Hash keys are written in insertion order.
+func builtinToJson(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'to_json'. Expected 1, got %d", len(args))
+	}
+
+	var out strings.Builder
+	if err := writeJSON(&out, args[0], nil); err != nil {
+		return objects.NewArgumentError("Cannot serialize to JSON: %s", err)
+	}
+
+	if err := e.checkStringSize(int64(out.Len())); err != nil {
+		return err
+	}
+
+	return &objects.String{Value: out.String()}
+}
+
+// "path" holds the collections that contain the current value, to detect cycles
+func writeJSON(out *strings.Builder, obj objects.Object, path []objects.Object) error {
+	switch obj := obj.(type) {
+	case *objects.Null:
+		out.WriteString("null")
+
+	case *objects.Boolean, *objects.Integer:
+		out.WriteString(obj.Inspect())
+
+	case *objects.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return fmt.Errorf("unsupported float value %s", obj.Inspect())
+		}
+		out.WriteString(obj.Inspect())
+
+	case *objects.String:
+		out.WriteString(jsonString(obj.Value))
+
+	case *objects.Array:
+		if slices.Contains(path, objects.Object(obj)) {
+			return errors.New("the array contains itself")
+		}
+
+		out.WriteByte('[')
+		for i, el := range obj.Elements {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			if err := writeJSON(out, el, append(path, obj)); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+
+	case objects.Ordered:
+		if slices.Contains(path, objects.Object(obj)) {
+			return errors.New("the hash contains itself")
+		}
+
+		out.WriteByte('{')
+		for i, pair := range obj.Pairs() {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			// JSON keys are always strings
+			key := pair.Key.Inspect()
+			if str, ok := pair.Key.(*objects.String); ok {
+				key = str.Value
+			}
+
+			out.WriteString(jsonString(key) + ":")
+			if err := writeJSON(out, pair.Value, append(path, obj)); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+
+	default:
+		return fmt.Errorf("unsupported type %s", obj.Type())
+	}
+
+	return nil
+}
+
+func jsonString(str string) string {
+	var buffer bytes.Buffer
+
+	enc := json.NewEncoder(&buffer)
+	enc.SetEscapeHTML(false)
+	enc.Encode(str)
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// from_json(s) parses a JSON string. Objects become hashes (keeping the order of their
+// keys), whole numbers become integers and any other number becomes a float.
+func builtinFromJson(e *Evaluator, env *objects.Storage, args ...objects.Object) objects.Object {
+	if len(args) != 1 {
+		return objects.NewArgumentError(
+			"Wrong number of arguments for 'from_json'. Expected 1, got %d", len(args))
+	}
+
+	str, ok := args[0].(*objects.String)
+	if !ok {
+		return objects.NewTypeError(
+			"Argument of 'from_json' must be a string. \n\tGot: %s", describeObject(args[0]))
+	}
+
+	dec := json.NewDecoder(strings.NewReader(str.Value))
+	dec.UseNumber()
+
+	value, err := readJSON(dec)
+	if err == nil {
+		// only a single value is allowed
+		if _, err = dec.Token(); err == io.EOF {
+			return value
+		} else if err == nil {
+			err = errors.New("unexpected data after the JSON value")
+		}
+	}
+
+	if err == io.EOF {
+		err = errors.New("unexpected end of JSON input")
+	}
+
+	return objects.NewArgumentError("Invalid JSON: %s", err)
+}
+
+func readJSON(dec *json.Decoder) (objects.Object, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '[':
+			elements := []objects.Object{}
+			for dec.More() {
+				el, err := readJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, el)
+			}
+
+			// step over "]"
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+
+			return &objects.Array{Elements: elements}, nil
+
+		case '{':
+			hash := objects.NewHash()
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := readJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+
+				hash.Set(&objects.String{Value: key.(string)}, value)
+			}
+
+			// step over "}"
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+
+			return hash, nil
+		}
+
+		return nil, fmt.Errorf("unexpected '%s'", token)
+
+	case json.Number:
+		if integer, err := token.Int64(); err == nil {
+			return &objects.Integer{Value: integer}, nil
+		}
+
+		float, err := token.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", token)
+		}
+
+		return &objects.Float{Value: float}, nil
+
+	case string:
+		return &objects.String{Value: token}, nil
+
+	case bool:
+		return selectBoolObject(token), nil
+
+	case nil:
+		return null_obj, nil
+	}
+
+	return nil, fmt.Errorf("unexpected token %v", token)
+}
@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/sl2.0/objects"
@@ -101,3 +102,114 @@ func parseAndEval(t *testing.T, input string) objects.Object {
 
 	return evaluated
 }
+
+func testError(t *testing.T, evaluated objects.Object, expected string) {
+	if evaluated.Type() != objects.ERROR_OBJ {
+		t.Errorf("Expected 'Object Error' type. Got %s", evaluated.Inspect())
+		return
+	}
+
+	if !strings.HasPrefix(evaluated.Inspect(), expected) {
+		t.Errorf("Bad message:\nExpected: \n%s\nActual: \n%s", expected, evaluated.Inspect())
+	}
+}
+
+func testNull(t *testing.T, evaluated objects.Object) {
+	if evaluated.Type() != objects.NULL_OBJ {
+		t.Errorf("Expected 'Object Null' type. Got %s", evaluated.Inspect())
+	}
+}
+
+// Expected error message on table driven tests. Used to distinguish errors from strings.
+type errorMessage string
+
+// Compares the evaluated object with the expected go value, using the test function of
+// the corresponding type.
+func testObject(t *testing.T, evaluated objects.Object, expected interface{}) {
+	switch expected := expected.(type) {
+	case int:
+		testInteger(t, evaluated, int64(expected))
+	case int64:
+		testInteger(t, evaluated, expected)
+	case bool:
+		testBool(t, evaluated, expected)
+	case string:
+		testString(t, evaluated, expected)
+	case errorMessage:
+		testError(t, evaluated, string(expected))
+	case nil:
+		testNull(t, evaluated)
+	default:
+		t.Errorf("Type of expected result not handled. Got %T", expected)
+	}
+}
+
+// Like testObject, but an expected string is the inspected representation of the resulting
+// array or hash. Used by the tests of operations that return collections.
+func testCollection(t *testing.T, evaluated objects.Object, expected interface{}) {
+	str, ok := expected.(string)
+	if !ok {
+		testObject(t, evaluated, expected)
+		return
+	}
+
+	if evaluated.Type() != objects.ARRAY_OBJ && evaluated.Type() != objects.HASH_OBJ {
+		t.Errorf("Expected an array or hash. Got %s", evaluated.Inspect())
+		return
+	}
+
+	if evaluated.Inspect() != str {
+		t.Errorf("Expected '%s'. Got %s", str, evaluated.Inspect())
+	}
+}
+
+// Checks the type and the inspected representation of collections
+func testInspect(t *testing.T, evaluated objects.Object, ty objects.ObjectType, expected string) {
+	if evaluated.Type() != ty {
+		t.Errorf("Expected '%s' type. Got %s", ty, evaluated.Inspect())
+		return
+	}
+
+	if evaluated.Inspect() != expected {
+		t.Errorf("Expected '%s'. Got %s", expected, evaluated.Inspect())
+	}
+}
+
+// Operations that traverse the keys of the hash "h", returning them as an array
+var keyOrderOperations = []struct {
+	name    string
+	program string
+}{
+	{name: "keys", program: `keys(h)`},
+	{name: "entries", program: `flat_map(entries(h), func(par) { retorna [par[0]]; })`},
+	{name: "merge", program: `keys(merge(h))`},
+	{name: "to_json", program: `keys(from_json(to_json(h)))`},
+	{
+		name: "for-in",
+		program: `var ks = [];
+		var i = 0;
+		repetir k en h {
+			splice(ks, i, 0, k);
+			i = i + 1;
+		}
+		ks`,
+	},
+}
+
+// Checks that every operation that traverses the keys of the hash "h" (declared by setup)
+// sees them on the same order: keys, entries, merge, to_json and for-in loops. The expected
+// keys are given as the inspected array and must be strings, because JSON only has string
+// keys.
+func testKeyOrder(t *testing.T, setup string, expected string) {
+	for _, op := range keyOrderOperations {
+		evaluated := parseAndEval(t, setup+"\n"+op.program)
+		if evaluated == nil {
+			continue
+		}
+
+		if evaluated.Inspect() != expected {
+			t.Errorf("Wrong key order using %s. Expected %s. Got %s",
+				op.name, expected, evaluated.Inspect())
+		}
+	}
+}
@@ -8,6 +8,10 @@ type Lexer struct {
 	currentPosition int // position of the current character
 	nextPosition    int // position of the next character
 	ch              byte
+
+	buffer []tokens.Token // tokens already read by a lookahead
+
+	ignoreLineBreaks bool // line breaks are skipped like any other white space
 }
 
 func NewLexer(input string) *Lexer {
@@ -16,24 +20,75 @@ func NewLexer(input string) *Lexer {
 	}
 
 	// initialize the lexer in a full working state
+	l.skipPreamble()
 	l.readChar()
 
 	return l
 }
 
+// Makes the lexer skip line breaks like any other white space, so no LINEBREAK tokens are
+// produced. Must be called before reading any token.
+func (l *Lexer) IgnoreLineBreaks() {
+	l.ignoreLineBreaks = true
+}
+
+// Returns a copy of the lexer on its current state. Reading tokens from the copy does not
+// advance the original lexer.
+func (l *Lexer) Clone() *Lexer {
+	clone := *l
+	clone.buffer = append([]tokens.Token(nil), l.buffer...)
+	return &clone
+}
+
 func (l *Lexer) NexToken() tokens.Token {
+	if len(l.buffer) > 0 {
+		token := l.buffer[0]
+		l.buffer = l.buffer[1:]
+		return token
+	}
+
+	return l.readToken()
+}
+
+// Returns the n-th upcoming token without consuming it. PeekToken(0) is the token that
+// the next call to NexToken will return.
+func (l *Lexer) PeekToken(n int) tokens.Token {
+	for len(l.buffer) <= n {
+		l.buffer = append(l.buffer, l.readToken())
+	}
+
+	return l.buffer[n]
+}
+
+func (l *Lexer) readToken() tokens.Token {
 	var token tokens.Token
 
 	l.burnWhiteSpaces()
 
-	// first search for comments and ignore them, consuming every
-	// character till the end of the line (or end of the file)
-	if l.ch == '/' && l.pickChar() == '/' {
-		for l.ch != '\n' && l.ch != 0 {
-			l.readChar()
+	// first search for comments and ignore them. Line comments consume every character
+	// till the end of the line (or end of the file), and block comments till the "*/"
+	for l.ch == '/' && (l.pickChar() == '/' || l.pickChar() == '*') {
+		if l.pickChar() == '/' {
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+			l.skipLineBreaks()
+			l.burnWhiteSpaces()
+			continue
 		}
-		l.skipLineBreaks()
+
+		multiline, err := l.skipBlockComment()
+		if err != "" {
+			return newMultiToken(tokens.ILLEGAL, err)
+		}
+
 		l.burnWhiteSpaces()
+
+		// a comment with line breaks separates statements, like the line breaks it contains
+		if multiline && !l.ignoreLineBreaks {
+			l.skipLineBreaks()
+			return newMultiToken(tokens.LINEBREAK, "")
+		}
 	}
 
 	// start generating tokens
@@ -71,6 +126,8 @@ func (l *Lexer) NexToken() tokens.Token {
 		// especial chars
 	case ',':
 		token = newSingleToken(tokens.COMMA, l.ch)
+	case '.':
+		token = newSingleToken(tokens.DOT, l.ch)
 	case ';':
 		token = newSingleToken(tokens.SEMICOLON, l.ch)
 	case ':':
@@ -83,15 +140,17 @@ func (l *Lexer) NexToken() tokens.Token {
 		token = newSingleToken(tokens.RPAR, l.ch)
 	case '(':
 		token = newSingleToken(tokens.LPAR, l.ch)
+	case '[':
+		token = newSingleToken(tokens.LBRACKET, l.ch)
+	case ']':
+		token = newSingleToken(tokens.RBRACKET, l.ch)
 	case '"':
-		str := ""
-		for l.pickChar() != '"' {
-			l.readChar()
-			str += string(l.ch)
+		str, err := l.extractString()
+		if err != "" {
+			token = newMultiToken(tokens.ILLEGAL, err)
+		} else {
+			token = newMultiToken(tokens.STRING, str)
 		}
-		// skeep the final '"'
-		l.readChar()
-		token = newMultiToken(tokens.STRING, str)
 	case '\n':
 		l.skipLineBreaks()
 		// early return to avoid errors with some multiline characters
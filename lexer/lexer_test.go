@@ -189,6 +189,21 @@ func TestNextToken(t *testing.T) {
 				{Type: tokens.EOF, Literal: ""},
 			},
 		},
+		{
+			`data["users"][0].name`,
+			[]tokens.Token{
+				{Type: tokens.IDENT, Literal: "data"},
+				{Type: tokens.LBRACKET, Literal: "["},
+				{Type: tokens.STRING, Literal: "users"},
+				{Type: tokens.RBRACKET, Literal: "]"},
+				{Type: tokens.LBRACKET, Literal: "["},
+				{Type: tokens.NUMBER, Literal: "0"},
+				{Type: tokens.RBRACKET, Literal: "]"},
+				{Type: tokens.DOT, Literal: "."},
+				{Type: tokens.IDENT, Literal: "name"},
+				{Type: tokens.EOF, Literal: ""},
+			},
+		},
 	}
 
 	for id, test := range testCases {
@@ -207,3 +222,144 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestStringEscapes(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected tokens.Token
+	}{
+		{`"caf\u00e9"`, tokens.Token{Type: tokens.STRING, Literal: "café"}},
+		{`"\u{1F600}!"`, tokens.Token{Type: tokens.STRING, Literal: "😀!"}},
+		{`"\x41\x7a"`, tokens.Token{Type: tokens.STRING, Literal: "Az"}},
+		{`"a\tb\n\"c\"\\"`, tokens.Token{Type: tokens.STRING, Literal: "a\tb\n\"c\"\\"}},
+		{`"ñandú"`, tokens.Token{Type: tokens.STRING, Literal: "ñandú"}},
+		{
+			`"\u12"`,
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "invalid escape sequence '\\u12' at line 1, column 2"},
+		},
+		{
+			"\n  \"ok\\x4\"",
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "invalid escape sequence '\\x4' at line 2, column 6"},
+		},
+		{
+			`"\u{110000}"`,
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "invalid escape sequence '\\u{110000}' at line 1, column 2"},
+		},
+		{
+			`"\uD800"`,
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "invalid escape sequence '\\uD800' at line 1, column 2"},
+		},
+		{
+			`"\q"`,
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "invalid escape sequence '\\q' at line 1, column 2"},
+		},
+		{
+			`x = "sin cerrar`,
+			tokens.Token{Type: tokens.ILLEGAL, Literal: "unterminated string at line 1, column 5"},
+		},
+	}
+
+	for _, tc := range testCases {
+		lexer := NewLexer(tc.input)
+
+		token := lexer.NexToken()
+		for token.Type != tokens.STRING && token.Type != tokens.ILLEGAL && token.Type != tokens.EOF {
+			token = lexer.NexToken()
+		}
+
+		if token != tc.expected {
+			t.Errorf("Input: %s\n\tExpected: %+v\n\tGot: %+v", tc.input, tc.expected, token)
+		}
+
+		if next := lexer.NexToken(); next.Type != tokens.EOF {
+			t.Errorf("Input: %s\n\tExpected EOF after the string. Got: %+v", tc.input, next)
+		}
+	}
+}
+
+func TestPreamble(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []tokens.TokenType
+	}{
+		{"\xEF\xBB\xBFvar a = 1;", []tokens.TokenType{tokens.VAR, tokens.IDENT, tokens.ASIGN, tokens.NUMBER, tokens.SEMICOLON}},
+		{"#!/usr/bin/env sl\nvar a = 1;", []tokens.TokenType{tokens.VAR, tokens.IDENT, tokens.ASIGN, tokens.NUMBER, tokens.SEMICOLON}},
+		{"\xEF\xBB\xBF#!/usr/bin/env sl\nretorna a", []tokens.TokenType{tokens.RETURN, tokens.IDENT}},
+		{"#!/usr/bin/env sl", []tokens.TokenType{}},
+		{ // only the first line can be a shebang
+			"a\n#!/sl",
+			[]tokens.TokenType{tokens.IDENT, tokens.LINEBREAK, tokens.ILLEGAL, tokens.BANG, tokens.SLASH, tokens.IDENT},
+		},
+	}
+
+	for _, tc := range testCases {
+		lexer := NewLexer(tc.input)
+
+		for i, ty := range append(tc.expected, tokens.EOF) {
+			token := lexer.NexToken()
+			if token.Type != ty {
+				t.Errorf("Input: %q\n\tExpected token %d to be %s. Got %s", tc.input, i, ty, token.Type)
+				break
+			}
+		}
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []tokens.TokenType
+	}{
+		{"a /* comentario */ + b", []tokens.TokenType{tokens.IDENT, tokens.PLUS, tokens.IDENT}},
+		{"/* uno */ /* dos */a", []tokens.TokenType{tokens.IDENT}},
+		{"a /* una\ndos */ b", []tokens.TokenType{tokens.IDENT, tokens.LINEBREAK, tokens.IDENT}},
+		{"a\n/* x\ny */\nb", []tokens.TokenType{tokens.IDENT, tokens.LINEBREAK, tokens.LINEBREAK, tokens.IDENT}},
+		{"a /* * / **/ 1 / 2", []tokens.TokenType{tokens.IDENT, tokens.NUMBER, tokens.SLASH, tokens.NUMBER}},
+		{"/* // */ a // /* b", []tokens.TokenType{tokens.IDENT}},
+		{"a /* sin cerrar", []tokens.TokenType{tokens.IDENT, tokens.ILLEGAL}},
+	}
+
+	for _, tc := range testCases {
+		lexer := NewLexer(tc.input)
+
+		for i, ty := range append(tc.expected, tokens.EOF) {
+			token := lexer.NexToken()
+			if token.Type != ty {
+				t.Errorf("Input: %q\n\tExpected token %d to be %s. Got %s", tc.input, i, ty, token.Type)
+				break
+			}
+		}
+	}
+
+	token := NewLexer("a\n  /* sin\ncerrar").PeekToken(2)
+	if token.Literal != "unterminated block comment at line 2, column 3" {
+		t.Errorf("Unexpected error message: %q", token.Literal)
+	}
+}
+
+func TestIgnoreLineBreaks(t *testing.T) {
+	lexer := NewLexer("a\n\n+ /* x\ny */ b // c\n;")
+	lexer.IgnoreLineBreaks()
+
+	expected := []tokens.TokenType{tokens.IDENT, tokens.PLUS, tokens.IDENT, tokens.SEMICOLON, tokens.EOF}
+	for i, ty := range expected {
+		if token := lexer.NexToken(); token.Type != ty {
+			t.Errorf("Expected token %d to be %s. Got %s", i, ty, token.Type)
+		}
+	}
+}
+
+func TestPeekToken(t *testing.T) {
+	lexer := NewLexer(`repetir x en items`)
+
+	if token := lexer.PeekToken(2); token.Type != tokens.IN {
+		t.Errorf("Expected peeked token %s. Got %s", tokens.IN, token.Type)
+	}
+
+	expected := []tokens.TokenType{tokens.FOR, tokens.IDENT, tokens.IN, tokens.IDENT, tokens.EOF}
+	for i, ty := range expected {
+		if token := lexer.NexToken(); token.Type != ty {
+			t.Errorf("Expected token %d to be %s. Got %s", i, ty, token.Type)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package lexer
 
-import "github.com/sl2.0/tokens"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sl2.0/tokens"
+)
 
 // generates a new "single char token" from the given token type and char
 func newSingleToken(ty tokens.TokenType, ch byte) tokens.Token {
@@ -67,8 +74,26 @@ func (l *Lexer) extractNumber() string {
 	return l.input[auxPos:l.currentPosition]
 }
 
+// Skips a UTF-8 byte order mark and a shebang line ("#!/usr/bin/env sl") at the start of
+// the input, so scripts can be run as executables
+func (l *Lexer) skipPreamble() {
+	const bom = "\xEF\xBB\xBF"
+	if strings.HasPrefix(l.input, bom) {
+		l.nextPosition = len(bom)
+	}
+
+	if strings.HasPrefix(l.input[l.nextPosition:], "#!") {
+		end := strings.IndexByte(l.input[l.nextPosition:], '\n')
+		if end == -1 {
+			l.nextPosition = len(l.input)
+		} else {
+			l.nextPosition += end + 1
+		}
+	}
+}
+
 func (l *Lexer) burnWhiteSpaces() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' || l.ch == '\n' && l.ignoreLineBreaks {
 		l.readChar()
 	}
 }
@@ -78,3 +103,145 @@ func (l *Lexer) skipLineBreaks() {
 		l.readChar()
 	}
 }
+
+// Skips a block comment starting on the opening "/*", leaving the lexer after the closing
+// "*/". Reports if the comment contains line breaks, or an error message if it is not
+// closed.
+func (l *Lexer) skipBlockComment() (bool, string) {
+	start := l.currentPosition
+	multiline := false
+
+	// step over "/*"
+	l.readChar()
+	l.readChar()
+
+	for l.ch != '*' || l.pickChar() != '/' {
+		switch l.ch {
+		case 0:
+			return false, l.errorAt(start, "unterminated block comment")
+		case '\n':
+			multiline = true
+		}
+
+		l.readChar()
+	}
+
+	// step over "*/"
+	l.readChar()
+	l.readChar()
+
+	return multiline, ""
+}
+
+// Reads a string literal starting on the opening '"', processing its escape sequences. The
+// lexer is left on the closing '"'. If the string is malformed an error message (with
+// the position of the problem) is returned instead.
+func (l *Lexer) extractString() (string, string) {
+	start := l.currentPosition
+	str := []byte{}
+	err := ""
+
+	for {
+		l.readChar()
+
+		switch l.ch {
+		case '"':
+			return string(str), err
+		case 0:
+			return "", l.errorAt(start, "unterminated string")
+		case '\\':
+			escStart := l.currentPosition
+			decoded, ok := l.readEscape()
+			if !ok && err == "" {
+				end := min(l.nextPosition, len(l.input))
+				err = l.errorAt(escStart, fmt.Sprintf(
+					"invalid escape sequence '%s'", l.input[escStart:end]))
+			}
+			str = append(str, decoded...)
+		default:
+			str = append(str, l.ch)
+		}
+	}
+}
+
+// Decodes the escape sequence that starts on the current '\\'. Supported sequences are
+// \n, \t, \r, \0, \\, \", \xFF (a byte), \u00e9 (4 hex digits) and \u{1F600} (a code point).
+func (l *Lexer) readEscape() ([]byte, bool) {
+	l.readChar()
+
+	switch l.ch {
+	case 'n':
+		return []byte{'\n'}, true
+	case 't':
+		return []byte{'\t'}, true
+	case 'r':
+		return []byte{'\r'}, true
+	case '0':
+		return []byte{0}, true
+	case '\\', '"':
+		return []byte{l.ch}, true
+	case 'x':
+		value, digits := l.readHexDigits(2)
+		if digits != 2 {
+			return nil, false
+		}
+
+		return []byte{byte(value)}, true
+	case 'u':
+		var value int64
+		var digits int
+
+		if l.pickChar() == '{' {
+			l.readChar()
+			value, digits = l.readHexDigits(6)
+			if digits == 0 || l.pickChar() != '}' {
+				return nil, false
+			}
+			l.readChar()
+		} else {
+			value, digits = l.readHexDigits(4)
+			if digits != 4 {
+				return nil, false
+			}
+		}
+
+		if !utf8.ValidRune(rune(value)) {
+			return nil, false
+		}
+
+		return utf8.AppendRune(nil, rune(value)), true
+	}
+
+	return nil, false
+}
+
+// Reads up to "max" hexadecimal digits after the current character, returning their value
+// and how many digits were read
+func (l *Lexer) readHexDigits(max int) (int64, int) {
+	start := l.nextPosition
+
+	for l.nextPosition-start < max && isHexDigit(l.pickChar()) {
+		l.readChar()
+	}
+
+	digits := l.nextPosition - start
+	if digits == 0 {
+		return 0, 0
+	}
+
+	value, _ := strconv.ParseInt(l.input[start:l.nextPosition], 16, 64)
+
+	return value, digits
+}
+
+func isHexDigit(ch byte) bool {
+	return isNumber(ch) || ch >= 'a' && ch <= 'f' || ch >= 'A' && ch <= 'F'
+}
+
+// Formats an error message with the line and column of the given position of the input
+func (l *Lexer) errorAt(position int, message string) string {
+	line := strings.Count(l.input[:position], "\n") + 1
+	column := position - strings.LastIndex(l.input[:position], "\n")
+
+	return fmt.Sprintf("%s at line %d, column %d", message, line, column)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/sl2.0/objects"
 	"github.com/sl2.0/repl"
 )
 
@@ -17,6 +18,8 @@ func main() {
 	mode := flag.String("mode", "eval", "Available modes: lexer, parser, eval(default)")
 	quiet := flag.Bool("quiet", false, "Suppres unnecesary messages")
 	maxTime := flag.Int64("max-time", 40000, "Max time for execution")
+	intFormat := flag.String("int-format", "plain", "Integer display format: plain(default), grouped, scientific")
+	strictDivision := flag.Bool("strict-division", false, "Make integer divisions with remainder an error")
 
 	inputFile := flag.String("file", "", "Execute the given file")
 	outputFile := flag.String("o", "", "File to output the result")
@@ -72,6 +75,21 @@ func main() {
 		log.Fatal("Invalid mode")
 	}
 
+	switch *intFormat {
+	case "plain":
+		builder = builder.WithIntegerFormat(objects.PlainFormat)
+	case "grouped":
+		builder = builder.WithIntegerFormat(objects.GroupedFormat)
+	case "scientific":
+		builder = builder.WithIntegerFormat(objects.ScientificFormat)
+	default:
+		log.Fatal("Invalid integer format")
+	}
+
+	if *strictDivision {
+		builder = builder.WithStrictDivision()
+	}
+
 	// Set max time execution for evaluation
 	builder = builder.WithTimeout(*maxTime)
 
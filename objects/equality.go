@@ -0,0 +1,87 @@
+package objects
+
+// Structural equality between objects. Arrays and hashes are equal when they have the
+// same (structurally equal) elements, while functions are only equal to themselves.
+// Integers and floats are compared by value, like the "==" operator does.
+//
+// Collections that contain themselves are supported: a pair of collections that is
+// already being compared is considered equal, so cycles are only followed once.
+func Equals(a, b Object) bool {
+	return equals(a, b, make(map[[2]Object]bool))
+}
+
+func equals(a, b Object, visited map[[2]Object]bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.Type() != b.Type() {
+		x, ok := floatValue(a)
+		y, okOther := floatValue(b)
+
+		return ok && okOther && x == y
+	}
+
+	switch a.(type) {
+	case *Array, *Hash:
+		pair := [2]Object{a, b}
+		if visited[pair] {
+			return true
+		}
+		visited[pair] = true
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value == b.(*Integer).Value
+	case *Float:
+		return a.Value == b.(*Float).Value
+	case *String:
+		return a.Value == b.(*String).Value
+	case *Boolean:
+		return a.Value == b.(*Boolean).Value
+	case *Null:
+		return true
+	case *Array:
+		other := b.(*Array)
+		if len(a.Elements) != len(other.Elements) {
+			return false
+		}
+
+		for i := range a.Elements {
+			if !equals(a.Elements[i], other.Elements[i], visited) {
+				return false
+			}
+		}
+
+		return true
+	case *Hash:
+		other := b.(*Hash)
+		if a.Len() != other.Len() {
+			return false
+		}
+
+		for _, pair := range a.Pairs() {
+			value, ok := other.Get(pair.Key.(Hashable))
+			if !ok || !equals(pair.Value, value, visited) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a == b
+}
+
+// Numeric value of integers and floats, used to compare them with each other
+func floatValue(obj Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return float64(obj.Value), true
+	case *Float:
+		return obj.Value, true
+	}
+
+	return 0, false
+}
@@ -2,6 +2,9 @@ package objects
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/sl2.0/ast"
 )
@@ -14,13 +17,19 @@ type Object interface {
 }
 
 const (
-	INTEGER_OBJ = "INTEGER"
-	STRING_OBJ  = "STRING"
-	BOOL_OBJ    = "BOOL"
-	NULL_OBJ    = "NULL"
-	ERROR_OBJ   = "ERROR"
-	RETURN_OBJ  = "RETURN"
-	FUNC_OBJ    = "FUNCTION"
+	INTEGER_OBJ  = "INTEGER"
+	FLOAT_OBJ    = "FLOAT"
+	STRING_OBJ   = "STRING"
+	BOOL_OBJ     = "BOOL"
+	NULL_OBJ     = "NULL"
+	ERROR_OBJ    = "ERROR"
+	RETURN_OBJ   = "RETURN"
+	BREAK_OBJ    = "BREAK"
+	CONTINUE_OBJ = "CONTINUE"
+	FUNC_OBJ     = "FUNCTION"
+	BUILTIN_OBJ  = "BUILTIN"
+	ARRAY_OBJ    = "ARRAY"
+	HASH_OBJ     = "HASH"
 )
 
 // --- Primitive data types ---
@@ -36,6 +45,70 @@ func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%v", i.Value)
 }
 
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
+// Floats always show a decimal point or an exponent, so they are not confused with integers
+func (f *Float) Inspect() string {
+	str := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".eIN") {
+		str += ".0"
+	}
+
+	return str
+}
+
+// Formats used to display integers
+type IntegerFormat int
+
+const (
+	PlainFormat      IntegerFormat = iota // 1000000
+	GroupedFormat                         // 1,000,000
+	ScientificFormat                      // 1e+06
+)
+
+// Returns the integer representation on the given format
+func (i *Integer) Format(format IntegerFormat) string {
+	digits := strconv.FormatInt(i.Value, 10)
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+
+	switch format {
+	case GroupedFormat:
+		var out strings.Builder
+		for idx, ch := range digits {
+			if idx > 0 && (len(digits)-idx)%3 == 0 {
+				out.WriteByte(',')
+			}
+			out.WriteRune(ch)
+		}
+
+		return sign + out.String()
+
+	case ScientificFormat:
+		// built from the digits, because float64 cannot hold every integer above 2^53
+		mantissa := digits[:1]
+		if decimals := strings.TrimRight(digits[1:], "0"); decimals != "" {
+			mantissa += "." + decimals
+		}
+
+		return fmt.Sprintf("%s%se+%02d", sign, mantissa, len(digits)-1)
+	}
+
+	return i.Inspect()
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: strconv.FormatInt(i.Value, 10)}
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -46,6 +119,9 @@ func (b *Boolean) Type() ObjectType {
 func (b *Boolean) Inspect() string {
 	return fmt.Sprintf("%v", b.Value)
 }
+func (b *Boolean) HashKey() HashKey {
+	return HashKey{Type: b.Type(), Value: strconv.FormatBool(b.Value)}
+}
 
 type String struct {
 	Value string
@@ -57,25 +133,149 @@ func (i *String) Type() ObjectType {
 func (i *String) Inspect() string {
 	return fmt.Sprintf("%v", i.Value)
 }
+func (i *String) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: i.Value}
+}
+func (i *String) Items() []Object {
+	items := []Object{}
+	for _, ch := range i.Value {
+		items = append(items, &String{Value: string(ch)})
+	}
+	return items
+}
+
+type Null struct{}
+
+func (n *Null) Type() ObjectType {
+	return NULL_OBJ
+}
+func (n *Null) Inspect() string {
+	return "null"
+}
 
 // --- Complex data types ---
 
 type ErrorObject struct {
 	error string
+	kind  ErrorKind
+}
+
+// Categories of errors, so hosts can handle them without looking at the messages
+type ErrorKind int
+
+const (
+	RuntimeError    ErrorKind = iota // errors without a more specific category
+	TypeError                        // values of the wrong type for an operation
+	NameError                        // identifiers and labels that cannot be resolved
+	DivisionByZero                   // integer divisions by zero
+	ArgumentError                    // wrong number of arguments or invalid argument values
+	IndexError                       // indexes out of range
+	LimitError                       // exceeded limits, like the recursion level or sizes
+	ArithmeticError                  // divisions with remainder on strict division mode
+	AssignmentError                  // assignments that are not allowed, like to functions
+	SyntaxError                      // statements used where they are not valid
+	IOError                          // failures writing the output
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case TypeError:
+		return "TypeError"
+	case NameError:
+		return "NameError"
+	case DivisionByZero:
+		return "DivisionByZero"
+	case ArgumentError:
+		return "ArgumentError"
+	case IndexError:
+		return "IndexError"
+	case LimitError:
+		return "LimitError"
+	case ArithmeticError:
+		return "ArithmeticError"
+	case AssignmentError:
+		return "AssignmentError"
+	case SyntaxError:
+		return "SyntaxError"
+	case IOError:
+		return "IOError"
+	}
+
+	return "RuntimeError"
 }
 
 func (b *ErrorObject) Type() ObjectType {
 	return ERROR_OBJ
 }
 
+// Creates an error without a specific category (RuntimeError)
 func NewError(format string, message ...interface{}) Object {
-	return &ErrorObject{error: fmt.Sprintf(format, message...)}
+	return newErrorOfKind(RuntimeError, format, message...)
+}
+
+func NewTypeError(format string, message ...interface{}) Object {
+	return newErrorOfKind(TypeError, format, message...)
+}
+
+func NewNameError(format string, message ...interface{}) Object {
+	return newErrorOfKind(NameError, format, message...)
+}
+
+func NewDivisionByZeroError(format string, message ...interface{}) Object {
+	return newErrorOfKind(DivisionByZero, format, message...)
+}
+
+func NewArgumentError(format string, message ...interface{}) Object {
+	return newErrorOfKind(ArgumentError, format, message...)
+}
+
+func NewIndexError(format string, message ...interface{}) Object {
+	return newErrorOfKind(IndexError, format, message...)
+}
+
+func NewLimitError(format string, message ...interface{}) Object {
+	return newErrorOfKind(LimitError, format, message...)
+}
+
+func NewArithmeticError(format string, message ...interface{}) Object {
+	return newErrorOfKind(ArithmeticError, format, message...)
+}
+
+func NewAssignmentError(format string, message ...interface{}) Object {
+	return newErrorOfKind(AssignmentError, format, message...)
+}
+
+func NewSyntaxError(format string, message ...interface{}) Object {
+	return newErrorOfKind(SyntaxError, format, message...)
+}
+
+func NewIOError(format string, message ...interface{}) Object {
+	return newErrorOfKind(IOError, format, message...)
+}
+
+func newErrorOfKind(kind ErrorKind, format string, message ...interface{}) Object {
+	return &ErrorObject{error: fmt.Sprintf(format, message...), kind: kind}
 }
 
 func (b *ErrorObject) Inspect() string {
 	return b.error
 }
 
+// Returns the message of the error, so hosts can handle specific errors
+func (b *ErrorObject) Message() string {
+	return b.error
+}
+
+// Returns the category of the error
+func (b *ErrorObject) Kind() ErrorKind {
+	return b.kind
+}
+
+// Reports whether the object is an error
+func IsError(obj Object) bool {
+	return obj != nil && obj.Type() == ERROR_OBJ
+}
+
 type ReturnObject struct {
 	Value Object
 }
@@ -87,6 +287,30 @@ func (r *ReturnObject) Inspect() string {
 	return fmt.Sprintf("%d", r.Value)
 }
 
+// Produced by "romper". Label is empty when the statement has no label.
+type BreakObject struct {
+	Label string
+}
+
+func (b *BreakObject) Type() ObjectType {
+	return BREAK_OBJ
+}
+func (b *BreakObject) Inspect() string {
+	return strings.TrimSpace("romper " + b.Label)
+}
+
+// Produced by "continuar". Label is empty when the statement has no label.
+type ContinueObject struct {
+	Label string
+}
+
+func (c *ContinueObject) Type() ObjectType {
+	return CONTINUE_OBJ
+}
+func (c *ContinueObject) Inspect() string {
+	return strings.TrimSpace("continuar " + c.Label)
+}
+
 type FunctionObject struct {
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
@@ -94,7 +318,7 @@ type FunctionObject struct {
 }
 
 func (f *FunctionObject) Type() ObjectType {
-	return RETURN_OBJ
+	return FUNC_OBJ
 }
 func (f *FunctionObject) Inspect() string {
 	s := "("
@@ -105,3 +329,171 @@ func (f *FunctionObject) Inspect() string {
 
 	return s + "\n" + f.Body.ToString(0)
 }
+
+type Array struct {
+	Elements []Object
+}
+
+func (a *Array) Type() ObjectType {
+	return ARRAY_OBJ
+}
+func (a *Array) Items() []Object {
+	items := make([]Object, len(a.Elements))
+	copy(items, a.Elements)
+	return items
+}
+func (a *Array) Inspect() string {
+	return inspectCollection(a, 0, nil, PlainFormat)
+}
+
+// Collections that can be traversed with a for-in loop
+type Iterable interface {
+	Object
+	Items() []Object
+}
+
+// Collections with a deterministic order of keys. Every operation that traverses the keys
+// (like for-in loops, keys, entries, merge or to_json) must go through this interface, so
+// all of them see the same order. Keys must be in the same order as Pairs.
+type Ordered interface {
+	Object
+	Keys() []Object
+	Pairs() []HashPair
+}
+
+// Only the objects that implement this interface can be used as hash keys
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+type HashKey struct {
+	Type  ObjectType
+	Value string
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hashes keep the insertion order of its keys, which is the order used to inspect and
+// iterate them.
+type Hash struct {
+	pairs map[HashKey]HashPair
+	keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{
+		pairs: make(map[HashKey]HashPair),
+	}
+}
+
+func (h *Hash) Type() ObjectType {
+	return HASH_OBJ
+}
+func (h *Hash) Inspect() string {
+	return inspectCollection(h, 0, nil, PlainFormat)
+}
+
+// Returns the keys in insertion order
+func (h *Hash) Keys() []Object {
+	pairs := h.Pairs()
+
+	keys := make([]Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+
+	return keys
+}
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+// Sets the value of the given key. New keys are placed at the end of the hash.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if _, ok := h.pairs[hashKey]; !ok {
+		h.keys = append(h.keys, hashKey)
+	}
+
+	h.pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+// Returns the key-value pairs in insertion order
+func (h *Hash) Pairs() []HashPair {
+	pairs := make([]HashPair, len(h.keys))
+	for i, key := range h.keys {
+		pairs[i] = h.pairs[key]
+	}
+
+	return pairs
+}
+
+func (h *Hash) Len() int {
+	return len(h.keys)
+}
+
+// Maximum nesting of collections displayed by Inspect. Deeper collections, and collections
+// that contain themselves, are displayed as "...".
+var MaxInspectDepth = 32
+
+// Returns the representation of the object, displaying integers (including the ones inside
+// arrays and hashes) on the given format.
+func InspectWithFormat(obj Object, format IntegerFormat) string {
+	switch obj := obj.(type) {
+	case *Integer:
+		return obj.Format(format)
+	case *Array, *Hash:
+		return inspectCollection(obj, 0, nil, format)
+	}
+
+	return obj.Inspect()
+}
+
+// Inspects an array or hash. "path" holds the collections that contain the current one.
+func inspectCollection(obj Object, depth int, path []Object, format IntegerFormat) string {
+	path = append(path, obj)
+
+	switch obj := obj.(type) {
+	case *Array:
+		elements := make([]string, len(obj.Elements))
+		for i, el := range obj.Elements {
+			elements[i] = inspectElement(el, depth+1, path, format)
+		}
+
+		return "[" + strings.Join(elements, ", ") + "]"
+	case *Hash:
+		pairs := make([]string, obj.Len())
+		for i, pair := range obj.Pairs() {
+			pairs[i] = inspectElement(pair.Key, depth+1, path, format) + ": " +
+				inspectElement(pair.Value, depth+1, path, format)
+		}
+
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+
+	return obj.Inspect()
+}
+
+// strings are quoted when they are inside a collection
+func inspectElement(obj Object, depth int, path []Object, format IntegerFormat) string {
+	switch obj := obj.(type) {
+	case *String:
+		return strconv.Quote(obj.Value)
+	case *Integer:
+		return obj.Format(format)
+	case *Array, *Hash:
+		if depth >= MaxInspectDepth || slices.Contains(path, obj) {
+			return "..."
+		}
+
+		return inspectCollection(obj, depth, path, format)
+	}
+
+	return obj.Inspect()
+}
@@ -0,0 +1,134 @@
+package objects
+
+import "testing"
+
+func TestErrorMessage(t *testing.T) {
+	obj := NewError("Cannot resolve identifier: %s", "x")
+
+	if !IsError(obj) {
+		t.Fatalf("Expected %s to be an error", obj.Type())
+	}
+
+	err, ok := obj.(*ErrorObject)
+	if !ok {
+		t.Fatalf("Expected *ErrorObject. Got %T", obj)
+	}
+
+	if err.Message() != "Cannot resolve identifier: x" {
+		t.Errorf("Unexpected error message: %q", err.Message())
+	}
+
+	if IsError(&Integer{Value: 1}) || IsError(nil) {
+		t.Errorf("Expected non error objects to not be reported as errors")
+	}
+}
+
+func TestInspectSelfReference(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "a"}, &Integer{Value: 1})
+	hash.Set(&String{Value: "self"}, hash)
+	hash.Set(&String{Value: "list"}, &Array{Elements: []Object{hash, hash}})
+
+	expected := `{"a": 1, "self": ..., "list": [..., ...]}`
+	if hash.Inspect() != expected {
+		t.Errorf("Expected %s. Got %s", expected, hash.Inspect())
+	}
+}
+
+func TestEqualsSelfReference(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "self"}, hash)
+
+	other := NewHash()
+	other.Set(&String{Value: "self"}, other)
+
+	if !Equals(hash, hash) || !Equals(hash, other) {
+		t.Errorf("Expected self referencing hashes with the same shape to be equal")
+	}
+
+	other.Set(&String{Value: "extra"}, &Integer{Value: 1})
+	if Equals(hash, other) {
+		t.Errorf("Expected self referencing hashes with different pairs to be different")
+	}
+}
+
+func TestInspectMaxDepth(t *testing.T) {
+	defer func(depth int) { MaxInspectDepth = depth }(MaxInspectDepth)
+	MaxInspectDepth = 2
+
+	array := &Array{Elements: []Object{&Integer{Value: 1}}}
+	for i := 0; i < 5; i++ {
+		array = &Array{Elements: []Object{array}}
+	}
+
+	expected := `[[...]]`
+	if array.Inspect() != expected {
+		t.Errorf("Expected %s. Got %s", expected, array.Inspect())
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	testCases := []struct {
+		value    float64
+		expected string
+	}{
+		{1.5, "1.5"},
+		{2, "2.0"},
+		{-0.25, "-0.25"},
+		{1e21, "1e+21"},
+	}
+
+	for _, tc := range testCases {
+		if actual := (&Float{Value: tc.value}).Inspect(); actual != tc.expected {
+			t.Errorf("Expected %s. Got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestHashKeysOrder(t *testing.T) {
+	var obj Object = NewHash()
+
+	hash, ok := obj.(Ordered)
+	if !ok {
+		t.Fatalf("Expected hashes to be ordered")
+	}
+
+	h := obj.(*Hash)
+	h.Set(&String{Value: "b"}, &Integer{Value: 1})
+	h.Set(&Integer{Value: 1}, &Integer{Value: 2})
+	h.Set(&String{Value: "a"}, &Integer{Value: 3})
+	h.Set(&String{Value: "b"}, &Integer{Value: 4})
+
+	keys := (&Array{Elements: hash.Keys()}).Inspect()
+	if keys != `["b", 1, "a"]` {
+		t.Errorf(`Expected keys ["b", 1, "a"]. Got %s`, keys)
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	testCases := []struct {
+		obj      Object
+		expected ErrorKind
+		name     string
+	}{
+		{obj: NewError("x"), expected: RuntimeError, name: "RuntimeError"},
+		{obj: NewTypeError("x"), expected: TypeError, name: "TypeError"},
+		{obj: NewNameError("x"), expected: NameError, name: "NameError"},
+		{obj: NewDivisionByZeroError("x"), expected: DivisionByZero, name: "DivisionByZero"},
+		{obj: NewArgumentError("x"), expected: ArgumentError, name: "ArgumentError"},
+		{obj: NewIndexError("x"), expected: IndexError, name: "IndexError"},
+		{obj: NewLimitError("x"), expected: LimitError, name: "LimitError"},
+		{obj: NewArithmeticError("x"), expected: ArithmeticError, name: "ArithmeticError"},
+		{obj: NewAssignmentError("x"), expected: AssignmentError, name: "AssignmentError"},
+		{obj: NewSyntaxError("x"), expected: SyntaxError, name: "SyntaxError"},
+		{obj: NewIOError("x"), expected: IOError, name: "IOError"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.obj.(*ErrorObject)
+
+		if err.Kind() != tc.expected || err.Kind().String() != tc.name {
+			t.Errorf("Expected kind %s. Got %s", tc.name, err.Kind())
+		}
+	}
+}
@@ -1,16 +1,22 @@
 package objects
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+)
 
 type Storage struct {
 	identifiers map[string]Object
-	outer       *Storage // outer environment
-	lvl         int      // to meassure recursion lvl
+	functions   map[string]bool // identifiers declared with "func" on this scope
+	outer       *Storage        // outer environment
+	lvl         int             // to meassure recursion lvl
+	frozen      bool            // read-only scope
 }
 
 func NewStorage() *Storage {
 	return &Storage{
 		identifiers: make(map[string]Object),
+		functions:   make(map[string]bool),
 		outer:       nil,
 		lvl:         0,
 	}
@@ -24,11 +30,24 @@ func NewEnclosedStorage(outer *Storage) (*Storage, error) {
 
 	return &Storage{
 		identifiers: make(map[string]Object),
+		functions:   make(map[string]bool),
 		outer:       outer,
 		lvl:         lvl,
 	}, nil
 }
 
+// Returns a copy of the scope, with the same outer scope and recursion level. The copy is
+// never frozen, and the functions of the scope are copied as plain values, so they can be
+// overridden on the copy.
+func (e *Storage) Clone() *Storage {
+	return &Storage{
+		identifiers: maps.Clone(e.identifiers),
+		functions:   make(map[string]bool),
+		outer:       e.outer,
+		lvl:         e.lvl,
+	}
+}
+
 func (e *Storage) Get(ident string) (Object, bool) {
 	value, ok := e.identifiers[ident]
 
@@ -39,7 +58,62 @@ func (e *Storage) Get(ident string) (Object, bool) {
 	return value, ok
 }
 
+// Declares (or overrides) the identifier on this scope. Returns an error object if the
+// scope is frozen.
 func (e *Storage) Set(ident string, obj Object) Object {
+	if e.frozen {
+		return NewAssignmentError("cannot modify frozen scope")
+	}
+
+	if e.functions[ident] {
+		return NewAssignmentError("cannot reassign function: %s", ident)
+	}
+
+	e.identifiers[ident] = obj
+	return obj
+}
+
+// Declares a named function on this scope. Functions can be declared again with the same
+// name, but cannot be reassigned with Set or Update.
+func (e *Storage) SetFunction(ident string, obj Object) Object {
+	if e.frozen {
+		return NewAssignmentError("cannot modify frozen scope")
+	}
+
+	e.functions[ident] = true
 	e.identifiers[ident] = obj
 	return obj
 }
+
+// Changes the value of an already declared identifier on the scope where it was declared.
+// When the identifier is not declared and the outermost scope is frozen, the frozen scope
+// error is returned instead of the unresolved identifier one.
+func (e *Storage) Update(ident string, obj Object) Object {
+	if _, ok := e.identifiers[ident]; !ok {
+		if e.outer != nil {
+			return e.outer.Update(ident, obj)
+		}
+
+		if e.frozen {
+			return NewAssignmentError("cannot modify frozen scope")
+		}
+
+		return NewNameError("Cannot resolve identifier: %s", ident)
+	}
+
+	if e.frozen {
+		return NewAssignmentError("cannot modify frozen scope")
+	}
+
+	if e.functions[ident] {
+		return NewAssignmentError("cannot reassign function: %s", ident)
+	}
+
+	e.identifiers[ident] = obj
+	return obj
+}
+
+// Makes the scope read-only. Used to protect injected globals and module namespaces.
+func (e *Storage) Freeze() {
+	e.frozen = true
+}
@@ -0,0 +1,84 @@
+package objects
+
+import (
+	"testing"
+)
+
+func TestFrozenStorage(t *testing.T) {
+	env := NewStorage()
+	env.Set("nuevo", &Integer{Value: 2})
+	env.Freeze()
+
+	value, ok := env.Get("nuevo")
+	if !ok {
+		t.Fatalf("Cannot read 'nuevo' from a frozen scope")
+	}
+
+	if value.Inspect() != "2" {
+		t.Errorf("Expected 2. Got %s", value.Inspect())
+	}
+
+	writes := []Object{
+		env.Set("nuevo", &Integer{Value: 3}),
+		env.Set("otro", &Integer{Value: 3}),
+		env.Update("nuevo", &Integer{Value: 3}),
+		// undeclared names cannot be written either
+		env.Update("noExiste", &Integer{Value: 3}),
+	}
+
+	for _, res := range writes {
+		if res.Type() != ERROR_OBJ || res.Inspect() != "cannot modify frozen scope" {
+			t.Errorf("Expected 'cannot modify frozen scope' error. Got %s", res.Inspect())
+		}
+	}
+
+	// enclosed scopes are still writable
+	local, err := NewEnclosedStorage(env)
+	if err != nil {
+		t.Fatalf("%s", err.Error())
+	}
+
+	if res := local.Set("nuevo", &Integer{Value: 4}); res.Type() == ERROR_OBJ {
+		t.Errorf("Expected enclosed scope to be writable. Got %s", res.Inspect())
+	}
+
+	value, _ = env.Get("nuevo")
+	if value.Inspect() != "2" {
+		t.Errorf("Frozen value was modified. Got %s", value.Inspect())
+	}
+}
+
+func TestStorageClone(t *testing.T) {
+	outer := NewStorage()
+	outer.Set("global", &Integer{Value: 1})
+
+	env, _ := NewEnclosedStorage(outer)
+	env.Set("local", &Integer{Value: 2})
+	env.SetFunction("f", &Null{})
+
+	clone := env.Clone()
+	clone.Set("local", &Integer{Value: 3})
+	clone.Update("global", &Integer{Value: 4})
+
+	// the copy does not change the original scope, but shares its outer scope
+	if value, _ := env.Get("local"); value.Inspect() != "2" {
+		t.Errorf("Expected the original 'local' to be 2. Got %s", value.Inspect())
+	}
+
+	if value, _ := env.Get("global"); value.Inspect() != "4" {
+		t.Errorf("Expected 'global' to be 4. Got %s", value.Inspect())
+	}
+
+	// functions of the original scope can be overridden on the copy
+	if res := clone.Set("f", &Integer{Value: 5}); res.Type() == ERROR_OBJ {
+		t.Errorf("Expected functions to be overridable on the copy. Got %s", res.Inspect())
+	}
+
+	if res := env.Set("f", &Null{}); res.Type() != ERROR_OBJ {
+		t.Errorf("Expected functions to remain protected on the original scope")
+	}
+
+	if clone.lvl != env.lvl {
+		t.Errorf("Expected recursion level %d. Got %d", env.lvl, clone.lvl)
+	}
+}
@@ -55,6 +55,17 @@ func (p *Parser) parseBoolExpression() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseNull() ast.Expression {
+	return &ast.NullLiteral{Token: p.currentToken}
+}
+
+// Illegal tokens are reported as errors. Malformed strings carry the reason (and position)
+// of the problem as their literal.
+func (p *Parser) parseIllegal() ast.Expression {
+	p.errors = append(p.errors, "Illegal token: "+p.currentToken.Literal)
+	return nil
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	exp := ast.NewIfExpression(p.currentToken)
 
@@ -84,9 +95,28 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	}
 
 	exp.Consequence = p.parseBlockStatement()
+	if exp.Consequence == nil {
+		return nil
+	}
 
 	// if not "else" block, return
-	if !p.curTokenIs(tokens.ELSE) {
+	if !p.nextTokenIs(tokens.ELSE) && !p.nextTokenIs(tokens.ELIF) {
+		return exp
+	}
+
+	p.advanceToken()
+
+	// "sino si" and "elif" chain another if expression
+	if p.curTokenIs(tokens.ELIF) || p.nextTokenIs(tokens.IF) {
+		if p.curTokenIs(tokens.ELSE) {
+			p.advanceToken()
+		}
+
+		exp.Alternative = p.parseElseIf()
+		if exp.Alternative == nil {
+			return nil
+		}
+
 		return exp
 	}
 
@@ -99,6 +129,22 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return exp
 }
 
+// Parses the if expression of an "else if" as the only statement of the alternative block
+func (p *Parser) parseElseIf() *ast.BlockStatement {
+	stmt := &ast.ExpressionStatement{
+		Token: p.currentToken,
+	}
+
+	nested := p.parseIfExpression()
+	if nested == nil {
+		return nil
+	}
+
+	stmt.Expression = nested
+
+	return &ast.BlockStatement{Statements: []ast.Statement{stmt}}
+}
+
 func (p *Parser) parseAnonnymousFunction() ast.Expression {
 	f := ast.NewAnonymousFunction(p.currentToken)
 
@@ -116,6 +162,62 @@ func (p *Parser) parseAnonnymousFunction() ast.Expression {
 	return f
 }
 
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := ast.NewArrayLiteral(p.currentToken)
+
+	elements := p.parseExpressionList(tokens.RBRACKET)
+	if elements == nil {
+		return nil
+	}
+
+	array.Elements = elements
+
+	return array
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := ast.NewHashLiteral(p.currentToken)
+
+	p.skipNextLineBreaks()
+
+	for !p.nextTokenIs(tokens.RBRAC) {
+		p.advanceToken()
+
+		key := p.parseExpression(LOWEST)
+		if key == nil {
+			return nil
+		}
+
+		if !p.advanceIfNextToken(tokens.COLON) {
+			p.errors = append(p.errors, "Missing ':' after hash key")
+			return nil
+		}
+
+		// step over ":"
+		p.advanceToken()
+
+		value := p.parseExpression(LOWEST)
+		if value == nil {
+			return nil
+		}
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		p.skipNextLineBreaks()
+
+		if !p.nextTokenIs(tokens.RBRAC) && !p.advanceIfNextToken(tokens.COMMA) {
+			return nil
+		}
+
+		p.skipNextLineBreaks()
+	}
+
+	// step over "}"
+	p.advanceToken()
+
+	return hash
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.advanceToken()
 
@@ -148,6 +250,30 @@ func (p *Parser) parseInfixExpression(e ast.Expression) ast.Expression {
 	return exp
 }
 
+// Assignments are right associative, so "a = b = 0" assigns 0 to both variables
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	case nil:
+		return nil
+	default:
+		p.errors = append(p.errors, "Invalid assignment target: "+target.TokenLiteral())
+		return nil
+	}
+
+	exp := ast.NewAssignExpression(p.currentToken, target)
+
+	// step over "="
+	p.advanceToken()
+
+	exp.Value = p.parseExpression(ASSIGN - 1)
+	if exp.Value == nil {
+		return nil
+	}
+
+	return exp
+}
+
 func (p *Parser) parseCall(e ast.Expression) ast.Expression {
 	f := ast.NewFunctionCall(p.currentToken, e)
 	f.Arguments = p.parseCallArguments()
@@ -155,33 +281,47 @@ func (p *Parser) parseCall(e ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseCallArguments() []ast.Expression {
-	args := []ast.Expression{}
+	return p.parseExpressionList(tokens.RPAR)
+}
 
-	// empty arguments
-	if p.nextTokenIs(tokens.RPAR) {
-		p.advanceToken()
-		return args
-	}
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	exp := ast.NewIndexExpression(p.currentToken, left)
 
+	// step over "["
 	p.advanceToken()
 
-	args = append(args, p.parseExpression(LOWEST))
+	exp.Index = p.parseExpression(LOWEST)
+	if exp.Index == nil {
+		return nil
+	}
 
-	for p.nextTokenIs(tokens.COMMA) {
-		// jump comma and place on next ident
-		p.advanceToken()
-		p.advanceToken()
-		args = append(args, p.parseExpression(LOWEST))
+	if !p.advanceIfNextToken(tokens.RBRACKET) {
+		p.errors = append(p.errors, "Missing closing ']' on index expression")
+		return nil
 	}
 
-	if !p.advanceIfNextToken(tokens.RPAR) {
+	return exp
+}
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := ast.NewMemberExpression(p.currentToken, left)
+
+	if !p.advanceIfNextToken(tokens.IDENT) {
+		p.errors = append(p.errors, "Expected member name after '.'")
 		return nil
 	}
 
-	return args
+	exp.Member = ast.NewIdentifier(p.currentToken)
+
+	return exp
 }
 
 func (p *Parser) parseForLoop() ast.Expression {
+	// "repetir item en items" needs two tokens of lookahead to be distinguished
+	if p.nextTokenIs(tokens.IDENT) && p.peekAt(2).Type == tokens.IN {
+		return p.parseForInLoop()
+	}
+
 	exp := ast.NewForLoop(p.currentToken)
 
 	if !p.advanceIfNextToken(tokens.NUMBER) {
@@ -200,3 +340,63 @@ func (p *Parser) parseForLoop() ast.Expression {
 
 	return exp
 }
+
+func (p *Parser) parseWhileLoop() ast.Expression {
+	exp := ast.NewWhileLoop(p.currentToken)
+
+	if !p.advanceIfNextToken(tokens.LPAR) {
+		p.errors = append(p.errors, "Missing '(' after while loop")
+		return nil
+	}
+
+	p.advanceToken()
+
+	condition := p.parseExpression(LOWEST)
+	if condition == nil {
+		return nil
+	}
+
+	exp.Condition = condition
+
+	if !p.advanceIfNextToken(tokens.RPAR) {
+		p.errors = append(p.errors, "Missing ')' on while loop")
+		return nil
+	}
+
+	if !p.advanceIfNextToken(tokens.LBRAC) {
+		p.errors = append(p.errors, "Missing opening '{' on while loop body")
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+
+	return exp
+}
+
+func (p *Parser) parseForInLoop() ast.Expression {
+	exp := ast.NewForInLoop(p.currentToken)
+
+	// step over "repetir"
+	p.advanceToken()
+	exp.Variable = ast.NewIdentifier(p.currentToken)
+
+	// step over "en"
+	p.advanceToken()
+	p.advanceToken()
+
+	iterable := p.parseExpression(LOWEST)
+	if iterable == nil {
+		return nil
+	}
+
+	exp.Iterable = iterable
+
+	if !p.advanceIfNextToken(tokens.LBRAC) {
+		p.errors = append(p.errors, "Missing opening '{' on for loop body")
+		return nil
+	}
+
+	exp.Body = p.parseBlockStatement()
+
+	return exp
+}
@@ -12,27 +12,40 @@ type (
 )
 
 type Parser struct {
-	lexer  *lexer.Lexer
-	errors []string
+	lexer    *lexer.Lexer
+	errors   []string
+	warnings []string // non fatal problems, like unreachable code
 
 	currentToken tokens.Token
 	nextToken    tokens.Token
 
 	infixParseFns  map[tokens.TokenType]infixFn
 	prefixParseFns map[tokens.TokenType]prefixFn
+
+	options Options
+}
+
+// Options that change the grammar accepted by the parser
+type Options struct {
+	// Line breaks never end statements, so statements can span many lines but must end with
+	// ";" (unless they end with a block or are the last one of a block or the program).
+	IgnoreLineBreaks bool
 }
 
 const (
 	LOWEST    = iota
+	ASSIGN    // =
 	EQUALS    // ==
 	GREATLESS // < >
 	SUM       // + -
 	PROD      // * /
 	PREFIX    // -X  !X
 	CALL      // foo(bar)
+	INDEX     // foo[bar] foo.bar
 )
 
 var precedences = map[string]int{
+	tokens.ASIGN:    ASSIGN,
 	tokens.EQUALS:   EQUALS,
 	tokens.NOTEQUAL: EQUALS,
 	tokens.LT:       GREATLESS,
@@ -43,13 +56,16 @@ var precedences = map[string]int{
 	tokens.SLASH:    PROD,
 	tokens.FUNCTION: CALL,
 	tokens.LPAR:     CALL,
+	tokens.LBRACKET: INDEX,
+	tokens.DOT:      INDEX,
 }
 
 // Generates a new parser using the given input string
 func NewParser(input string) *Parser {
 	parser := &Parser{
-		lexer:  lexer.NewLexer(input),
-		errors: []string{},
+		lexer:    lexer.NewLexer(input),
+		errors:   []string{},
+		warnings: []string{},
 
 		infixParseFns:  make(map[tokens.TokenType]infixFn),
 		prefixParseFns: make(map[tokens.TokenType]prefixFn),
@@ -60,11 +76,25 @@ func NewParser(input string) *Parser {
 	return parser
 }
 
+// Generates a new parser using the given input string and parsing options
+func NewParserWithOptions(input string, options Options) *Parser {
+	lex := lexer.NewLexer(input)
+	if options.IgnoreLineBreaks {
+		lex.IgnoreLineBreaks()
+	}
+
+	parser := NewParserFromLexer(lex)
+	parser.options = options
+
+	return parser
+}
+
 // Returns a new parser using the tokens from a custom lexer
 func NewParserFromLexer(lexer *lexer.Lexer) *Parser {
 	parser := &Parser{
-		lexer:  lexer,
-		errors: []string{},
+		lexer:    lexer,
+		errors:   []string{},
+		warnings: []string{},
 
 		infixParseFns:  make(map[tokens.TokenType]infixFn),
 		prefixParseFns: make(map[tokens.TokenType]prefixFn),
@@ -87,10 +117,15 @@ func (parser *Parser) InitParsingFns() {
 	parser.registerPrefixFn(tokens.STRING, parser.parseString)
 	parser.registerPrefixFn(tokens.TRUE, parser.parseBoolExpression)
 	parser.registerPrefixFn(tokens.FALSE, parser.parseBoolExpression)
+	parser.registerPrefixFn(tokens.NULL, parser.parseNull)
+	parser.registerPrefixFn(tokens.ILLEGAL, parser.parseIllegal)
 	parser.registerPrefixFn(tokens.LPAR, parser.parseGroupedExpression)
 	parser.registerPrefixFn(tokens.IF, parser.parseIfExpression)
 	parser.registerPrefixFn(tokens.FUNCTION, parser.parseAnonnymousFunction)
 	parser.registerPrefixFn(tokens.FOR, parser.parseForLoop)
+	parser.registerPrefixFn(tokens.WHILE, parser.parseWhileLoop)
+	parser.registerPrefixFn(tokens.LBRACKET, parser.parseArrayLiteral)
+	parser.registerPrefixFn(tokens.LBRAC, parser.parseHashLiteral)
 
 	parser.registerInfixFn(tokens.MINUS, parser.parseInfixExpression)
 	parser.registerInfixFn(tokens.PLUS, parser.parseInfixExpression)
@@ -101,19 +136,30 @@ func (parser *Parser) InitParsingFns() {
 	parser.registerInfixFn(tokens.EQUALS, parser.parseInfixExpression)
 	parser.registerInfixFn(tokens.NOTEQUAL, parser.parseInfixExpression)
 	parser.registerInfixFn(tokens.LPAR, parser.parseCall)
+	parser.registerInfixFn(tokens.LBRACKET, parser.parseIndexExpression)
+	parser.registerInfixFn(tokens.DOT, parser.parseMemberExpression)
+	parser.registerInfixFn(tokens.ASIGN, parser.parseAssignExpression)
 }
 
 func (p *Parser) ParseProgram() *ast.Program {
 	tree := &ast.Program{}
 	tree.Statements = []ast.Statement{}
 
+	var reachability reachability
+
 	for !p.curTokenIs(tokens.EOF) {
+		errors := len(p.errors)
 		stmt := p.parseStatement()
 
 		if stmt != nil {
+			p.checkReachable(&reachability, stmt)
 			tree.Statements = append(tree.Statements, stmt)
 		}
 
+		if len(p.errors) == errors {
+			p.checkStatementEnd()
+		}
+
 		p.advanceToken()
 	}
 
@@ -127,8 +173,22 @@ func (p *Parser) parseStatement() ast.Statement {
 	case tokens.RETURN:
 		return p.parseReturnStatement()
 	case tokens.FUNCTION:
+		// "func(...)" at the start of a statement is an anonymous function
+		if p.nextTokenIs(tokens.LPAR) {
+			return p.parseExpressionStatement()
+		}
 		return p.parseFunctionStatement()
-	case tokens.LINEBREAK:
+	case tokens.BREAK:
+		return p.parseBreakStatement()
+	case tokens.CONTINUE:
+		return p.parseContinueStatement()
+	case tokens.IDENT:
+		if p.nextTokenIs(tokens.COLON) {
+			return p.parseLabeledLoop()
+		}
+		return p.parseExpressionStatement()
+	case tokens.LINEBREAK, tokens.SEMICOLON:
+		// empty statements are skipped
 		return nil
 	default:
 		return p.parseExpressionStatement()
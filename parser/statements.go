@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	"github.com/sl2.0/ast"
 	"github.com/sl2.0/tokens"
 )
@@ -46,6 +48,75 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{
+		Token: p.currentToken,
+		Label: p.parseLoopLabel(),
+	}
+
+	if p.nextTokenIs(tokens.SEMICOLON) {
+		p.advanceToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{
+		Token: p.currentToken,
+		Label: p.parseLoopLabel(),
+	}
+
+	if p.nextTokenIs(tokens.SEMICOLON) {
+		p.advanceToken()
+	}
+
+	return stmt
+}
+
+// Parses the optional label after "romper" and "continuar"
+func (p *Parser) parseLoopLabel() *ast.Identifier {
+	if !p.nextTokenIs(tokens.IDENT) {
+		return nil
+	}
+
+	p.advanceToken()
+
+	return ast.NewIdentifier(p.currentToken)
+}
+
+// Parses a loop preceded by a label, like "externo: repetir x en items { ... }". The label
+// can be used by "romper" and "continuar" inside nested loops.
+func (p *Parser) parseLabeledLoop() ast.Statement {
+	label := ast.NewIdentifier(p.currentToken)
+
+	// step over ":"
+	p.advanceToken()
+
+	if !p.nextTokenIs(tokens.FOR) && !p.nextTokenIs(tokens.WHILE) {
+		p.errors = append(p.errors, fmt.Sprintf("Expected a loop after label '%s'", label.Value))
+		return nil
+	}
+
+	p.advanceToken()
+
+	stmt := p.parseExpressionStatement()
+	if stmt == nil {
+		return nil
+	}
+
+	switch loop := stmt.Expression.(type) {
+	case *ast.ForLoop:
+		loop.Label = label
+	case *ast.ForInLoop:
+		loop.Label = label
+	case *ast.WhileLoop:
+		loop.Label = label
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	stmt := &ast.VarStatement{
 		Token: p.currentToken,
@@ -66,7 +137,9 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 
 	stmt.Value = p.parseExpression(LOWEST)
 
-	p.advanceToken()
+	if p.nextTokenIs(tokens.SEMICOLON) {
+		p.advanceToken()
+	}
 
 	return stmt
 }
@@ -80,19 +153,26 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		return nil
 	}
 
+	var reachability reachability
+
 	for !p.curTokenIs(tokens.RBRAC) && !p.curTokenIs(tokens.EOF) {
+		errors := len(p.errors)
 		stmt := p.parseStatement()
 
 		if stmt != nil {
+			p.checkReachable(&reachability, stmt)
 			block.Statements = append(block.Statements, stmt)
 		}
 
-		if !p.curTokenIs(tokens.RBRAC) {
-			p.advanceToken()
+		if len(p.errors) == errors {
+			p.checkStatementEnd()
 		}
+
+		p.advanceToken()
 	}
 
-	if !p.advanceIfCurToken(tokens.RBRAC) {
+	// the block ends on its closing "}", like any other expression ends on its last token
+	if !p.curTokenIs(tokens.RBRAC) {
 		p.errors = append(p.errors, "Missing closing '}' on block statement")
 		return nil
 	}
@@ -132,6 +212,15 @@ func (p *Parser) parseFuncParameters() []*ast.Identifier {
 
 	for !p.curTokenIs(tokens.RPAR) {
 		ident := ast.NewIdentifier(p.currentToken)
+
+		// the second declaration would silently shadow the first one
+		for _, param := range params {
+			if param.Value == ident.Value {
+				p.errors = append(p.errors, "duplicate parameter name: "+ident.Value)
+				break
+			}
+		}
+
 		params = append(params, ident)
 
 		p.advanceToken()
@@ -147,3 +236,23 @@ func (p *Parser) parseFuncParameters() []*ast.Identifier {
 
 	return params
 }
+
+// Tracks the return statements of a block (or the program) to find unreachable code
+type reachability struct {
+	returned bool // a return statement was found
+	reported bool // the unreachable code was already reported
+}
+
+// Adds a warning if the statement follows a return statement of the same block. Only the
+// first unreachable statement of each block is reported.
+func (p *Parser) checkReachable(r *reachability, stmt ast.Statement) {
+	if r.returned && !r.reported {
+		r.reported = true
+		msg := fmt.Sprintf("Unreachable code after return statement: '%s'", stmt.TokenLiteral())
+		p.warnings = append(p.warnings, msg)
+	}
+
+	if _, ok := stmt.(*ast.ReturnStatement); ok {
+		r.returned = true
+	}
+}
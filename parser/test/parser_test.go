@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/sl2.0/ast"
+	"github.com/sl2.0/parser"
 )
 
 func TestFuncCall(t *testing.T) {
@@ -86,3 +87,531 @@ func TestFuncCall(t *testing.T) {
 		}
 	}
 }
+
+func TestIndexAndMemberChains(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input: `data["users"][0].name`,
+			expected: `member expression:
+ left:
+    index expression:
+     left:
+        index expression:
+         left:
+            Identifier: data
+         index:
+            String: users
+     index:
+        Integer: 0
+ member: name`,
+		},
+		{
+			input: `obj.a.b`,
+			expected: `member expression:
+ left:
+    member expression:
+     left:
+        Identifier: obj
+     member: a
+ member: b`,
+		},
+	}
+
+	for _, tc := range testCases {
+		testExpressionTree(t, tc.input, tc.expected)
+	}
+}
+
+func TestDumpTokens(t *testing.T) {
+	input := `var x = [1];`
+	expected := `[Type: VAR, Literal: 'var']
+[Type: IDENT, Literal: 'x']
+[Type: ASIGN, Literal: '=']
+[Type: LBRACKET, Literal: '[']
+[Type: NUMBER, Literal: '1']
+[Type: RBRACKET, Literal: ']']
+[Type: SEMICOLON, Literal: ';']
+[Type: EOF, Literal: '']
+`
+
+	p := parser.NewParser(input)
+
+	// dumping twice ensures the parser state is not consumed
+	for i := 0; i < 2; i++ {
+		actual := p.DumpTokens()
+		if actual != expected {
+			t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+		}
+	}
+
+	program := p.ParseProgram()
+	if p.HasErrors() || len(program.Statements) != 1 {
+		t.Errorf("Parser state was modified after dumping the tokens")
+	}
+}
+
+func TestUnreachableCodeWarning(t *testing.T) {
+	testCases := []struct {
+		input    string
+		warnings int
+	}{
+		{
+			input: `func nuevo() {
+				retorna 1;
+				var x = 2;
+				x + 1;
+			}`,
+			warnings: 1,
+		},
+		{
+			input: `func nuevo(a) {
+				si (a > 1) {
+					retorna 1;
+				}
+				retorna 2;
+			}`,
+			warnings: 0,
+		},
+		{input: "retorna 1; x", warnings: 1},
+		{input: `retorna 1
+			var x = 2
+			x`, warnings: 1},
+		{input: "var x = 1; retorna x", warnings: 0},
+		{
+			// each block reports its own unreachable code
+			input: `func nuevo() {
+				retorna 1;
+				2;
+			}
+			retorna 3;
+			4`,
+			warnings: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := parser.NewParser(tc.input)
+		p.ParseProgram()
+
+		if p.HasErrors() {
+			t.Fatalf("Unexpected parsing errors: %v", p.Errors())
+		}
+
+		if len(p.Warnings()) != tc.warnings {
+			t.Errorf("Expected %d warnings. Got %d: %v", tc.warnings, len(p.Warnings()), p.Warnings())
+		}
+	}
+}
+
+func TestForLoopLookahead(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input: `repetir item en [1, 2] { item }`,
+			expected: `for in loop:
+ variable: item
+ iterable:
+    array literal:
+      elements:
+        Integer: 1
+        Integer: 2
+ body:
+    block statement:
+      expression statement:
+       expression: 
+          Identifier: item`,
+		},
+		{
+			input: `repetir 2 { item }`,
+			expected: `for loop:
+ iterations: Integer: 2
+
+ body:
+    block statement:
+      expression statement:
+       expression: 
+          Identifier: item`,
+		},
+	}
+
+	for _, tc := range testCases {
+		testExpressionTree(t, tc.input, tc.expected)
+	}
+}
+
+func TestNegativeCollectionElements(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input: `[-1, -2]`,
+			expected: `array literal:
+  elements:
+    prefix expression:
+     operator: -
+     right:
+        Integer: 1
+    prefix expression:
+     operator: -
+     right:
+        Integer: 2`,
+		},
+		{
+			input: `[1, -2 - 3]`,
+			expected: `array literal:
+  elements:
+    Integer: 1
+    infix expression:
+     left:
+        prefix expression:
+         operator: -
+         right:
+            Integer: 2
+     operator: -
+     right:
+        Integer: 3`,
+		},
+		{
+			input: `{-1: "a", 2: -3}`,
+			expected: `hash literal:
+  key:
+    prefix expression:
+     operator: -
+     right:
+        Integer: 1
+  value:
+    String: a
+  key:
+    Integer: 2
+  value:
+    prefix expression:
+     operator: -
+     right:
+        Integer: 3`,
+		},
+	}
+
+	for _, tc := range testCases {
+		testExpressionTree(t, tc.input, tc.expected)
+	}
+}
+
+func TestEmptyStatements(t *testing.T) {
+	testCases := []struct {
+		input      string
+		statements int
+	}{
+		{input: `1;; 2;`, statements: 2},
+		{input: `;1;`, statements: 1},
+		{input: "var a = 1;;\n;\nretorna a;;", statements: 2},
+		{input: `func nuevo() { ;; retorna 1;; }`, statements: 1},
+	}
+
+	for _, tc := range testCases {
+		p := generateProgram(t, tc.input)
+
+		if len(p.Statements) != tc.statements {
+			t.Errorf("Expected %d statements on '%s'. Got %d", tc.statements, tc.input, len(p.Statements))
+		}
+
+		for _, stmt := range p.Statements {
+			if stmt == nil {
+				t.Errorf("Found a nil statement on '%s'", tc.input)
+			}
+		}
+	}
+
+	p := generateProgram(t, `func nuevo() { ;; retorna 1;; }`)
+	f, ok := p.Statements[0].(*ast.FunctionStatement)
+	if !ok {
+		t.Fatalf("Cannot convert statement to ast.FunctionStatement")
+	}
+
+	if len(f.Body.Statements) != 1 {
+		t.Errorf("Expected 1 statement on the function body. Got %d", len(f.Body.Statements))
+	}
+}
+
+func TestMalformedStringError(t *testing.T) {
+	p := parser.NewParser(`var a = "hola \u00g1";`)
+	p.ParseProgram()
+
+	expected := "Illegal token: invalid escape sequence '\\u00' at line 1, column 15"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("Expected error %q. Got %v", expected, p.Errors())
+	}
+}
+
+func TestDuplicateParameters(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: `func f(x, x) { retorna x; }`, expected: []string{"duplicate parameter name: x"}},
+		{input: `var g = func(a, b, a, b) { retorna a; };`, expected: []string{
+			"duplicate parameter name: a",
+			"duplicate parameter name: b",
+		}},
+		{input: `func h(x, y) { retorna x; }`, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		p := parser.NewParser(tc.input)
+		p.ParseProgram()
+
+		if strings.Join(p.Errors(), "\n") != strings.Join(tc.expected, "\n") {
+			t.Errorf("Expected errors %q on '%s'. Got %q", tc.expected, tc.input, p.Errors())
+		}
+	}
+}
+
+func TestElseIfChains(t *testing.T) {
+	elif := generateProgram(t, `si (a) { 1 } elif (b) { 2 } elif (c) { 3 } sino { 4 }`)
+	elseIf := generateProgram(t, `si (a) { 1 } sino si (b) { 2 } sino si (c) {
+		3
+	} sino { 4 }`)
+
+	if len(elif.Statements) != 1 || len(elseIf.Statements) != 1 {
+		t.Fatalf("Expected 1 statement. Got %d and %d", len(elif.Statements), len(elseIf.Statements))
+	}
+
+	if elif.ToString(0) != elseIf.ToString(0) {
+		t.Errorf("Expected 'elif' to parse as 'sino si'.\nGot:\n%s\nExpected:\n%s",
+			elif.ToString(0), elseIf.ToString(0))
+	}
+
+	// every "elif" is an if expression nested on the alternative of the previous one
+	exp := elif.Statements[0].(*ast.ExpressionStatement).Expression
+	for _, condition := range []string{"a", "b", "c"} {
+		ifExp, ok := exp.(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("Cannot convert %T to ast.IfExpression", exp)
+		}
+
+		if ifExp.Condition.TokenLiteral() != condition {
+			t.Errorf("Expected condition %s. Got %s", condition, ifExp.Condition.TokenLiteral())
+		}
+
+		if ifExp.Alternative == nil || len(ifExp.Alternative.Statements) != 1 {
+			t.Fatalf("Expected a single statement on the alternative of '%s'", condition)
+		}
+
+		exp = ifExp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression
+	}
+
+	if exp.TokenLiteral() != "4" {
+		t.Errorf("Expected the last alternative to be 4. Got %s", exp.TokenLiteral())
+	}
+}
+
+func TestBlockCommentsBetweenStatements(t *testing.T) {
+	testCases := []struct {
+		input      string
+		statements int
+	}{
+		{input: "a\n/* x\ny */\nb", statements: 2},
+		{input: "a /* x\ny */ -b", statements: 2},
+		{input: "a /* x */ - b", statements: 1},
+		{input: "var x = /* uno */ 1;\n/*\n * varias\n * lineas\n */\nx", statements: 2},
+		{input: "[1, /* dos\n */ 2]", statements: 1},
+	}
+
+	for _, tc := range testCases {
+		p := generateProgram(t, tc.input)
+
+		if len(p.Statements) != tc.statements {
+			t.Errorf("Expected %d statements on %q. Got %d", tc.statements, tc.input, len(p.Statements))
+		}
+	}
+}
+
+func TestLabeledLoops(t *testing.T) {
+	p := generateProgram(t, `externo: mientras (true) {
+		repetir x en items {
+			romper externo
+			continuar;
+		}
+	}`)
+
+	if len(p.Statements) != 1 {
+		t.Fatalf("Expected 1 statement. Got %d", len(p.Statements))
+	}
+
+	stmt, ok := p.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Cannot convert statement to ast.ExpressionStatement")
+	}
+
+	loop, ok := stmt.Expression.(*ast.WhileLoop)
+	if !ok {
+		t.Fatalf("Cannot convert expression to ast.WhileLoop")
+	}
+
+	if loop.Label == nil || loop.Label.Value != "externo" {
+		t.Fatalf("Expected loop label 'externo'. Got %v", loop.Label)
+	}
+
+	inner := loop.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.ForInLoop)
+	if inner.Label != nil {
+		t.Errorf("Expected inner loop without label. Got %s", inner.Label.Value)
+	}
+
+	brk, ok := inner.Body.Statements[0].(*ast.BreakStatement)
+	if !ok || brk.Label == nil || brk.Label.Value != "externo" {
+		t.Errorf("Expected 'romper externo'. Got %s", inner.Body.Statements[0].ToString(0))
+	}
+
+	cont, ok := inner.Body.Statements[1].(*ast.ContinueStatement)
+	if !ok || cont.Label != nil {
+		t.Errorf("Expected 'continuar' without label. Got %s", inner.Body.Statements[1].ToString(0))
+	}
+
+	parser := parser.NewParser(`externo: 1 + 2`)
+	parser.ParseProgram()
+
+	expected := "Expected a loop after label 'externo'"
+	if len(parser.Errors()) == 0 || parser.Errors()[0] != expected {
+		t.Errorf("Expected error %q. Got %v", expected, parser.Errors())
+	}
+}
+
+func TestAssignmentAssociativity(t *testing.T) {
+	p := generateProgram(t, `a = b[0] = 1 + 2`)
+
+	outer, ok := p.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("Cannot convert expression to ast.AssignExpression")
+	}
+
+	if outer.Target.(*ast.Identifier).Value != "a" {
+		t.Errorf("Expected outer target 'a'. Got %s", outer.Target.TokenLiteral())
+	}
+
+	inner, ok := outer.Value.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("Expected the value of the assignment to be another assignment. Got %s",
+			outer.Value.ToString(0))
+	}
+
+	if _, ok := inner.Target.(*ast.IndexExpression); !ok {
+		t.Errorf("Expected inner target to be an index expression. Got %s", inner.Target.ToString(0))
+	}
+
+	if _, ok := inner.Value.(*ast.InfixExpression); !ok {
+		t.Errorf("Expected inner value to be '1 + 2'. Got %s", inner.Value.ToString(0))
+	}
+
+	parser := parser.NewParser(`1 = 2`)
+	parser.ParseProgram()
+
+	expected := "Invalid assignment target: 1"
+	if len(parser.Errors()) == 0 || parser.Errors()[0] != expected {
+		t.Errorf("Expected error %q. Got %v", expected, parser.Errors())
+	}
+}
+
+func TestStatementsAfterBlocks(t *testing.T) {
+	testCases := []struct {
+		input      string
+		statements int
+	}{
+		{input: `func uno() { retorna 1; } uno()`, statements: 2},
+		{input: `si (true) { 1 } sino { 2 }; 3`, statements: 2},
+		{input: `repetir 2 { 1 } mientras (false) { 2 }`, statements: 2},
+		{input: `var f = func() { retorna 1; }
+		f()`, statements: 2},
+		{input: `func(x) { retorna x; }(1)`, statements: 1},
+	}
+
+	for _, tc := range testCases {
+		p := generateProgram(t, tc.input)
+
+		if len(p.Statements) != tc.statements {
+			t.Errorf("Expected %d statements on '%s'. Got %d", tc.statements, tc.input, len(p.Statements))
+		}
+	}
+}
+
+func TestSemicolonInsideLists(t *testing.T) {
+	testCases := []string{
+		`f(func() { retorna 1; };, 2)`,
+		`f(func() { retorna 1; };)`,
+		`[func() { retorna 1; };]`,
+	}
+
+	for _, input := range testCases {
+		p := parser.NewParser(input)
+		p.ParseProgram()
+
+		if !p.HasErrors() {
+			t.Errorf("Expected parsing errors on '%s'", input)
+		}
+	}
+}
+
+func TestIgnoreLineBreaksOption(t *testing.T) {
+	input := `var total = 1
+		+ 2
+		* 3;
+	si (total == 7) {
+		total
+	}
+	total
+	- 1`
+
+	p := parser.NewParserWithOptions(input, parser.Options{IgnoreLineBreaks: true})
+	program := p.ParseProgram()
+
+	if p.HasErrors() {
+		t.Fatalf("Unexpected parsing errors: %v", p.Errors())
+	}
+
+	if len(program.Statements) != 3 {
+		t.Fatalf("Expected 3 statements. Got %d", len(program.Statements))
+	}
+
+	value := program.Statements[0].(*ast.VarStatement).Value
+	if _, ok := value.(*ast.InfixExpression); !ok {
+		t.Errorf("Expected the multi-line value to be an infix expression. Got %T", value)
+	}
+
+	last := program.Statements[2].(*ast.ExpressionStatement).Expression
+	if _, ok := last.(*ast.InfixExpression); !ok {
+		t.Errorf("Expected 'total - 1' to be an infix expression. Got %T", last)
+	}
+
+	// line breaks end statements by default
+	if program := generateProgram(t, "var total = 1\n-1"); len(program.Statements) != 2 {
+		t.Errorf("Expected 2 statements without options. Got %d", len(program.Statements))
+	}
+}
+
+func TestIgnoreLineBreaksRequiresSemicolons(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "var a = 1\nvar b = 2", expected: []string{"Expected ';' at the end of the statement. Got VAR"}},
+		{input: "a\nb\nc", expected: []string{
+			"Expected ';' at the end of the statement. Got IDENT",
+			"Expected ';' at the end of the statement. Got IDENT",
+		}},
+		{input: "func f() { retorna 1 }\nf()", expected: nil},
+		{input: "var a = 1; /* uno\ndos */ a", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		p := parser.NewParserWithOptions(tc.input, parser.Options{IgnoreLineBreaks: true})
+		p.ParseProgram()
+
+		if strings.Join(p.Errors(), "\n") != strings.Join(tc.expected, "\n") {
+			t.Errorf("Expected errors %q on %q. Got %q", tc.expected, tc.input, p.Errors())
+		}
+	}
+}
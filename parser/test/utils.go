@@ -7,6 +7,7 @@ Here are utility functions for the main tests. The real test suit is on
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/sl2.0/ast"
@@ -48,6 +49,29 @@ func generateProgram(t *testing.T, input string) *ast.Program {
 	return p
 }
 
+/*
+Parse an input with a single expression statement and compare the printed tree of its
+expression with the expected one. Leading and trailing spaces are ignored.
+*/
+func testExpressionTree(t *testing.T, input string, expected string) {
+	p := generateProgram(t, input)
+
+	if len(p.Statements) != 1 {
+		t.Fatalf("Number of statements found: %d", len(p.Statements))
+	}
+
+	stmt, ok := p.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Cannot convert statement to ast.ExpressionStatement")
+	}
+
+	expected = strings.TrimSpace(expected)
+	actual := strings.TrimSpace(stmt.Expression.ToString(0))
+	if actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
 func testLiteralExpression(t *testing.T, expression ast.Expression, expected interface{}) bool {
 	if expression == nil {
 		t.Errorf("Wtf bro, you submited a nil expression")
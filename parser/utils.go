@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/sl2.0/ast"
 	"github.com/sl2.0/tokens"
 )
 
@@ -19,6 +21,43 @@ func (p *Parser) HasErrors() bool {
 	return len(p.errors) != 0
 }
 
+// Warnings are problems that do not prevent the program from running
+func (p *Parser) Warnings() []string {
+	return p.warnings
+}
+
+// Lists the upcoming tokens (starting from the current one) till the end of the input.
+// The parser state is not modified, so it can be called at any moment for debugging.
+func (p *Parser) DumpTokens() string {
+	var buffer bytes.Buffer
+
+	writeToken := func(t tokens.Token) {
+		buffer.WriteString(fmt.Sprintf("[Type: %v, Literal: '%v']\n", t.Type, t.Literal))
+	}
+
+	writeToken(p.currentToken)
+	if p.curTokenIs(tokens.EOF) {
+		return buffer.String()
+	}
+
+	writeToken(p.nextToken)
+	if p.nextTokenIs(tokens.EOF) {
+		return buffer.String()
+	}
+
+	lexer := p.lexer.Clone()
+	for {
+		token := lexer.NexToken()
+		writeToken(token)
+
+		if token.Type == tokens.EOF {
+			break
+		}
+	}
+
+	return buffer.String()
+}
+
 func (p *Parser) registerInfixFn(t tokens.TokenType, f infixFn) {
 	p.infixParseFns[t] = f
 }
@@ -47,6 +86,19 @@ func (p *Parser) nextPrecendence() int {
 	return value
 }
 
+// Returns the token "n" positions ahead, where 0 is the current token and 1 the next one.
+// Allows to disambiguate constructs that need more than one token of lookahead.
+func (p *Parser) peekAt(n int) tokens.Token {
+	switch n {
+	case 0:
+		return p.currentToken
+	case 1:
+		return p.nextToken
+	}
+
+	return p.lexer.PeekToken(n - 2)
+}
+
 // Compares the current token type with the expected type.
 func (p *Parser) curTokenIs(expTy tokens.TokenType) bool {
 	return p.currentToken.Type == expTy
@@ -71,6 +123,67 @@ func (p *Parser) advanceIfNextToken(expTy tokens.TokenType) bool {
 	return false
 }
 
+// Parses a comma separated list of expressions until the given closing token. Line breaks
+// between the elements and a trailing comma are allowed.
+func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	p.skipNextLineBreaks()
+
+	// empty list
+	if p.nextTokenIs(end) {
+		p.advanceToken()
+		return list
+	}
+
+	p.advanceToken()
+	list = append(list, p.parseExpression(LOWEST))
+	p.skipNextLineBreaks()
+
+	for p.nextTokenIs(tokens.COMMA) {
+		// jump comma and place on next element
+		p.advanceToken()
+		p.skipNextLineBreaks()
+
+		if p.nextTokenIs(end) {
+			break
+		}
+
+		p.advanceToken()
+		list = append(list, p.parseExpression(LOWEST))
+		p.skipNextLineBreaks()
+	}
+
+	if !p.advanceIfNextToken(end) {
+		return nil
+	}
+
+	return list
+}
+
+// When line breaks are ignored, statements must end with ";" unless they end with a block
+// or are the last one of a block or the program. Called after parsing a statement.
+func (p *Parser) checkStatementEnd() {
+	if !p.options.IgnoreLineBreaks {
+		return
+	}
+
+	if p.curTokenIs(tokens.SEMICOLON) || p.curTokenIs(tokens.RBRAC) ||
+		p.nextTokenIs(tokens.RBRAC) || p.nextTokenIs(tokens.EOF) {
+		return
+	}
+
+	msg := fmt.Sprintf("Expected ';' at the end of the statement. Got %s", p.nextToken.Type)
+	p.errors = append(p.errors, msg)
+}
+
+// Advances while the next token is a line break
+func (p *Parser) skipNextLineBreaks() {
+	for p.nextTokenIs(tokens.LINEBREAK) {
+		p.advanceToken()
+	}
+}
+
 func (p *Parser) advanceIfCurToken(expTy tokens.TokenType) bool {
 	if p.currentToken.Type == expTy {
 		p.advanceToken()
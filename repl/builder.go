@@ -52,6 +52,18 @@ func (r ReplBuilder) WithTimeout(miliseconds int64) ReplBuilder {
 	return r
 }
 
+// Format used to display integer results
+func (r ReplBuilder) WithIntegerFormat(format objects.IntegerFormat) ReplBuilder {
+	r.repl.integerFormat = format
+	return r
+}
+
+// Makes integer divisions with remainder an error instead of truncating the result
+func (r ReplBuilder) WithStrictDivision() ReplBuilder {
+	r.repl.strictDivision = true
+	return r
+}
+
 func (r ReplBuilder) Interactive() ReplBuilder {
 	r.repl.interactive = true
 	return r
@@ -37,7 +37,10 @@ type Repl struct {
 	rlInstance *readline.Instance
 	env        *objects.Storage
 
-	maxTime int64
+	maxTime       int64
+	integerFormat objects.IntegerFormat
+
+	strictDivision bool
 }
 
 func (r Repl) Run() {
@@ -150,6 +153,7 @@ func (r Repl) parse(in string) {
 	// Parse and output results
 	p := parser.NewParser(in)
 	program := p.ParseProgram()
+	printErrors(r.errFile, p.Warnings())
 
 	if len(p.Errors()) != 0 {
 		printErrors(r.errFile, p.Errors()) // Print errors if any
@@ -160,15 +164,31 @@ func (r Repl) parse(in string) {
 }
 
 func (r Repl) execute(in string) {
+	// ":type <expression>" prints the type of the expression instead of its value
+	if expression, ok := strings.CutPrefix(strings.TrimSpace(in), ":type "); ok {
+		typeName, err := evaluator.NewWithEnvironment(r.env).TypeOf(expression)
+		if err != nil {
+			printErrors(r.errFile, []string{err.Error()})
+		} else {
+			fmt.Fprintln(r.outFile, typeName)
+		}
+		return
+	}
+
 	// Parse and evaluate the complete input
 	p := parser.NewParser(in)
 	program := p.ParseProgram()
+	printErrors(r.errFile, p.Warnings())
 
 	if len(p.Errors()) != 0 {
 		printErrors(r.errFile, p.Errors())
 	} else {
 		ev := evaluator.NewFromProgram(program)
+		ev.SetIntegerFormat(r.integerFormat)
+		ev.SetStrictDivision(r.strictDivision)
+		ev.SetOutput(r.outFile)
 		evaluated := ev.EvalProgram(r.env)
+		printErrors(r.errFile, ev.Warnings())
 
 		if ev.HasErrors() {
 			printErrors(r.errFile, ev.Errors())
@@ -176,7 +196,7 @@ func (r Repl) execute(in string) {
 		}
 
 		if evaluated != nil {
-			fmt.Fprintln(r.outFile, evaluated.Inspect())
+			fmt.Fprintln(r.outFile, ev.Inspect(evaluated))
 		} else {
 			fmt.Fprintln(r.outFile, "No returned values")
 		}
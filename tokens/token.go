@@ -15,8 +15,13 @@ const (
 	IDENT    = "IDENT"
 	IF       = "IF"
 	ELSE     = "ELSE"
+	ELIF     = "ELIF" // same as "sino si"
 	FOR      = "FOR"
+	WHILE    = "WHILE"
+	IN       = "IN"
 	RETURN   = "RETURN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
 	DATATYPE = "DATATYPE" // a datatype declaration token
 
 	// primitive data types
@@ -24,6 +29,7 @@ const (
 	STRING = "STRING"
 	TRUE   = "TRUE"
 	FALSE  = "FALSE"
+	NULL   = "NULL"
 
 	// especial characters
 	COLON     = "COLON"     // :
@@ -38,33 +44,42 @@ const (
 	ASTERISC = "ASTERISC" // *
 	BANG     = "BANG"     // !
 	COMMA    = "COMMA"    // ,
+	DOT      = "DOT"      // .
 	ASIGN    = "ASIGN"    // =
 	EQUALS   = "EQUALS"   // ==
 	NOTEQUAL = "NOTEQUAL" // !=
 	SLASH    = "STROKE"
 
 	// brackets and parenteses
-	LBRAC = "LBRAC" // {
-	RBRAC = "RBRAC" // }
-	LPAR  = "LPAR"  // (
-	RPAR  = "RPAR"  // )
-	LT    = "LT"    // <
-	GT    = "GT"    // >
+	LBRAC    = "LBRAC"    // {
+	RBRAC    = "RBRAC"    // }
+	LPAR     = "LPAR"     // (
+	RPAR     = "RPAR"     // )
+	LBRACKET = "LBRACKET" // [
+	RBRACKET = "RBRACKET" // ]
+	LT       = "LT"       // <
+	GT       = "GT"       // >
 )
 
 var keywords = map[string]TokenType{
-	"func":    FUNCTION,
-	"var":     VAR,
-	"si":      IF,
-	"sino":    ELSE,
-	"repetir": FOR,
-	"retorna": RETURN,
+	"func":      FUNCTION,
+	"var":       VAR,
+	"si":        IF,
+	"sino":      ELSE,
+	"elif":      ELIF,
+	"repetir":   FOR,
+	"mientras":  WHILE,
+	"en":        IN,
+	"retorna":   RETURN,
+	"romper":    BREAK,
+	"continuar": CONTINUE,
 
 	// datatype keywords
 	"entero": DATATYPE,
 	"cadena": DATATYPE,
 	"true":   TRUE,
 	"false":  FALSE,
+	"null":   NULL,
 }
 
 func ResolveType(ident string) TokenType {